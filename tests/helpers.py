@@ -0,0 +1,417 @@
+"""Run the check in-process against a fake discovery server and instances.
+
+Discovery and every instance share one local HTTP server: /state lists the
+announcements, and each instance answers health checks under its own path.
+"""
+
+import io
+import json
+import os
+import select
+import shutil
+import socket
+import socketserver
+import ssl
+import struct
+import sys
+import tempfile
+import threading
+import time
+import types
+import unittest
+from collections import namedtuple
+from contextlib import redirect_stderr, redirect_stdout
+from http.server import BaseHTTPRequestHandler, ThreadingHTTPServer
+from importlib.machinery import SourceFileLoader
+from urllib.parse import parse_qs, urlparse
+
+SCRIPT = os.path.join(os.path.dirname(__file__), os.pardir, "otpl-service-check")
+TLS = os.path.join(os.path.dirname(os.path.abspath(__file__)), "tls")
+
+
+def load_check():
+    loader = SourceFileLoader("otpl_service_check", SCRIPT)
+    module = types.ModuleType(loader.name)
+    # Registered so that probes can be pickled for the worker pool.
+    sys.modules[loader.name] = module
+    loader.exec_module(module)
+    return module
+
+
+check = load_check()
+
+
+def next_of(values):
+    # Answered in turn, the last one repeating.
+    if len(values) > 1:
+        return values.pop(0)
+    return values[0]
+
+
+# peer is the client certificate's subject, if one was presented over TLS;
+# server is the address the request came in on.
+Request = namedtuple("Request", "path headers peer version server")
+
+
+class Instance(object):
+    def __init__(self, name, statuses, delay, bodies, headers, routes, fields):
+        self.name = name
+        self.statuses = list(statuses)
+        self.delay = delay
+        # None for a small JSON document
+        self.bodies = list(bodies)
+        self.headers = headers
+        # status by path under the instance, e.g. {"ready": 503}, overriding
+        # statuses; or a function of the Request returning (status, body)
+        self.routes = routes
+        # extra announcement fields, overriding the defaults
+        self.fields = fields
+        # Request for each request
+        self.requests = []
+
+    @property
+    def probes(self):
+        return len(self.requests)
+
+    def next_reply(self, request):
+        self.requests.append(request)
+        route = request.path.split("?")[0].split("/", 2)[-1]
+        if route in self.routes:
+            reply = self.routes[route]
+            if callable(reply):
+                return reply(request)
+            return reply, None
+        return next_of(self.statuses), next_of(self.bodies)
+
+
+class IPv6HTTPServer(ThreadingHTTPServer):
+    address_family = socket.AF_INET6
+
+
+class FakeService(object):
+    def __init__(self, service="svc"):
+        self.service = service
+        self.instances = {}
+        self.state_delay = 0
+        self.state_requests = 0
+        # turns the announcement list into the discovery document
+        self.state_wrap = None
+        self.state_headers = {}
+        # (path, headers) of each discovery request
+        self.state_log = []
+        # answer /state?service=NAME with only that service's announcements
+        self.service_query = False
+        self.protocol_version = "HTTP/1.0"
+        # answer anything else with 505, like some legacy servers
+        self.only_http10 = False
+        # seconds added to the time in Date headers
+        self.clock_offset = 0
+        # health checks in flight, and the most at once, by serviceType
+        self.active = {}
+        self.max_active = {}
+        self.lock = threading.Lock()
+        self.servers = []
+        self.url = "http://127.0.0.1:%s/" % self.serve(None)
+        self.tls_url = None
+
+    def serve(self, context, address=("127.0.0.1", 0)):
+        server_class = ThreadingHTTPServer
+        if ":" in address[0]:
+            server_class = IPv6HTTPServer
+        server = server_class(address, self.handler())
+        server.daemon_threads = True
+        if context is not None:
+            server.socket = context.wrap_socket(server.socket, server_side=True)
+        thread = threading.Thread(target=server.serve_forever)
+        thread.daemon = True
+        thread.start()
+        self.servers.append(server)
+        return server.server_address[1]
+
+    def serve_tls(self):
+        # Instances can then be announced at tls_uri(name) too.  Client
+        # certificates are asked for, but not required.
+        context = ssl.SSLContext(ssl.PROTOCOL_TLS_SERVER)
+        context.load_cert_chain(
+            os.path.join(TLS, "server.pem"), os.path.join(TLS, "server.key")
+        )
+        context.load_verify_locations(os.path.join(TLS, "ca.pem"))
+        context.verify_mode = ssl.CERT_OPTIONAL
+        self.tls_url = "https://localhost:%s/" % self.serve(context)
+
+    def serve_ipv6(self):
+        # Answers on [::1] too, at the same port as url, as a dual-stack host
+        # would.  Raises OSError where there's no IPv6 loopback.
+        port = int(self.url.rsplit(":", 1)[1].strip("/"))
+        self.serve(None, ("::1", port))
+
+    def close(self):
+        for server in self.servers:
+            server.shutdown()
+            server.server_close()
+
+    def announce(
+        self,
+        name,
+        statuses=(200,),
+        delay=0,
+        bodies=(None,),
+        headers=None,
+        routes=None,
+        chunks=None,
+        **fields
+    ):
+        self.instances[name] = Instance(
+            name, statuses, delay, bodies, headers or {}, routes or {}, fields
+        )
+        # (delay, bytes) pairs sent as a chunked body instead
+        self.instances[name].chunks = chunks
+        return self.instances[name]
+
+    def withdraw(self, name):
+        del self.instances[name]
+
+    def probes(self):
+        return dict((name, i.probes) for name, i in self.instances.items())
+
+    def uri(self, name):
+        return "%s%s/" % (self.url, name)
+
+    def tls_uri(self, name):
+        return "%s%s/" % (self.tls_url, name)
+
+    def announcement(self, name):
+        ann = {
+            "announcementId": name,
+            "serviceType": self.service,
+            "serviceUri": self.uri(name),
+            "environment": "prod",
+            "metadata": {},
+        }
+        ann.update(self.instances[name].fields)
+        return ann
+
+    def state(self):
+        return [self.announcement(name) for name in sorted(self.instances)]
+
+    def handler(self):
+        fake = self
+
+        class Handler(BaseHTTPRequestHandler):
+            @property
+            def protocol_version(self):
+                return fake.protocol_version
+
+            def do_GET(self):
+                if self.path.startswith("/state"):
+                    with fake.lock:
+                        fake.state_requests += 1
+                        fake.state_log.append((self.path, dict(self.headers)))
+                        state = fake.state()
+                    query = parse_qs(urlparse(self.path).query)
+                    if fake.service_query and "service" in query:
+                        services = query["service"]
+                        state = [a for a in state if a["serviceType"] in services]
+                    if fake.state_wrap is not None:
+                        state = fake.state_wrap(state)
+                    time.sleep(fake.state_delay)
+                    return self.reply(200, state, fake.state_headers)
+                name = self.path.strip("/").split("/")[0]
+                with fake.lock:
+                    instance = fake.instances.get(name)
+                    if instance is not None:
+                        reply = instance.next_reply(self.request_info())
+                if instance is None:
+                    return self.reply(404, {"error": "no such instance"})
+                status, body = reply
+                if fake.only_http10 and self.request_version != "HTTP/1.0":
+                    return self.reply(505, {"error": "HTTP/1.0 only"})
+                with fake.lock:
+                    service = fake.announcement(name)["serviceType"]
+                    active = fake.active.get(service, 0) + 1
+                    fake.active[service] = active
+                    fake.max_active[service] = max(
+                        active, fake.max_active.get(service, 0)
+                    )
+                time.sleep(instance.delay)
+                with fake.lock:
+                    fake.active[service] -= 1
+                if instance.chunks is not None:
+                    return self.reply_chunked(status, instance.chunks)
+                if body is None:
+                    body = {"instance": name}
+                self.reply(status, body, instance.headers)
+
+            def date_time_string(self, timestamp=None):
+                if timestamp is None:
+                    timestamp = time.time() + fake.clock_offset
+                return BaseHTTPRequestHandler.date_time_string(self, timestamp)
+
+            def request_info(self):
+                peer = None
+                if isinstance(self.connection, ssl.SSLSocket):
+                    cert = self.connection.getpeercert()
+                    if cert:
+                        peer = dict(rdn[0] for rdn in cert["subject"])
+                return Request(
+                    self.path,
+                    dict(self.headers),
+                    peer,
+                    self.request_version,
+                    self.server.server_address[0],
+                )
+
+            def reply(self, status, body, headers=None):
+                # body is bytes, or a document to send as JSON.
+                headers = dict(headers or {})
+                if not isinstance(body, bytes):
+                    body = json.dumps(body).encode("utf-8")
+                    headers.setdefault("Content-Type", "application/json")
+                self.send_response(status)
+                for name, value in sorted(headers.items()):
+                    self.send_header(name, value)
+                if "Content-Length" not in headers:
+                    self.send_header("Content-Length", str(len(body)))
+                self.end_headers()
+                try:
+                    self.wfile.write(body)
+                except (BrokenPipeError, ConnectionResetError):
+                    pass  # The check gave up on us.
+
+            def reply_chunked(self, status, chunks):
+                # Written by hand so that it's chunked even over HTTP/1.0.
+                try:
+                    self.wfile.write(
+                        b"HTTP/1.1 %d OK\r\n"
+                        b"Content-Type: application/json\r\n"
+                        b"Transfer-Encoding: chunked\r\n"
+                        b"Connection: close\r\n\r\n" % status
+                    )
+                    for delay, chunk in chunks:
+                        self.wfile.flush()
+                        time.sleep(delay)
+                        self.wfile.write(b"%x\r\n%s\r\n" % (len(chunk), chunk))
+                    self.wfile.write(b"0\r\n\r\n")
+                except (BrokenPipeError, ConnectionResetError):
+                    pass
+                self.close_connection = True
+
+            def log_message(self, *args):
+                pass
+
+        return Handler
+
+
+class FakeSocks5(object):
+    # A SOCKS5 proxy without authentication that only CONNECTs, recording
+    # each (host, port) asked for.
+    def __init__(self):
+        self.connects = []
+        self.server = socketserver.ThreadingTCPServer(("127.0.0.1", 0), self.handler())
+        self.server.daemon_threads = True
+        self.address = "127.0.0.1:%s" % self.server.server_address[1]
+        thread = threading.Thread(target=self.server.serve_forever)
+        thread.daemon = True
+        thread.start()
+
+    def close(self):
+        self.server.shutdown()
+        self.server.server_close()
+
+    def handler(self):
+        proxy = self
+
+        class Handler(socketserver.BaseRequestHandler):
+            def read(self, n):
+                data = b""
+                while len(data) < n:
+                    chunk = self.request.recv(n - len(data))
+                    if not chunk:
+                        raise EOFError()
+                    data += chunk
+                return data
+
+            def handle(self):
+                try:
+                    _, nmethods = self.read(2)
+                    self.read(nmethods)
+                    self.request.sendall(b"\x05\x00")
+                    _, cmd, _, atyp = self.read(4)
+                    if atyp == 1:
+                        host = socket.inet_ntoa(self.read(4))
+                    elif atyp == 3:
+                        host = self.read(self.read(1)[0]).decode("ascii")
+                    else:
+                        host = socket.inet_ntop(socket.AF_INET6, self.read(16))
+                    (port,) = struct.unpack("!H", self.read(2))
+                except EOFError:
+                    return
+                proxy.connects.append((host, port))
+                try:
+                    upstream = socket.create_connection((host, port), timeout=5)
+                except (OSError, ValueError):
+                    self.request.sendall(b"\x05\x05\x00\x01" + b"\x00" * 6)
+                    return
+                self.request.sendall(b"\x05\x00\x00\x01" + b"\x00" * 6)
+                self.relay(upstream)
+
+            def relay(self, upstream):
+                socks = [self.request, upstream]
+                try:
+                    while True:
+                        readable, _, _ = select.select(socks, [], [], 5)
+                        if not readable:
+                            return
+                        for sock in readable:
+                            data = sock.recv(65536)
+                            if not data:
+                                return
+                            other = upstream if sock is self.request else self.request
+                            other.sendall(data)
+                finally:
+                    upstream.close()
+
+        return Handler
+
+
+class CheckTestCase(unittest.TestCase):
+    def setUp(self):
+        self.fake = FakeService()
+        self.addCleanup(self.fake.close)
+        self.tmp = tempfile.mkdtemp()
+        self.addCleanup(shutil.rmtree, self.tmp)
+
+    def path(self, name):
+        return os.path.join(self.tmp, name)
+
+    def base_args(self):
+        return ["-d", self.fake.url, "-s", self.fake.service]
+
+    def main(self, *args):
+        saved = sys.argv
+        sys.argv = ["otpl-service-check"] + self.base_args() + list(args)
+        try:
+            return check.Main()
+        finally:
+            sys.argv = saved
+
+    def run_check(self, *args):
+        # Returns the exit code and output lines.
+        out = io.StringIO()
+        with redirect_stdout(out):
+            code = self.main(*args).run()
+        return code, out.getvalue().splitlines()
+
+    def assertLine(self, lines, text):
+        if not any(text in line for line in lines):
+            self.fail("no line with %r in:\n%s" % (text, "\n".join(lines)))
+
+    def assertParserError(self, args, text, code=3):
+        # Bad arguments exit unknown, with a usage message, except where
+        # argparse itself rejects them with code 2.
+        out = io.StringIO()
+        with redirect_stdout(out), redirect_stderr(out):
+            with self.assertRaises(SystemExit) as cm:
+                self.main(*args)
+        self.assertEqual(cm.exception.code, code)
+        self.assertIn(text, out.getvalue())
@@ -0,0 +1,39 @@
+import gzip
+import unittest
+
+from helpers import CheckTestCase
+
+
+class AcceptEncodingTest(CheckTestCase):
+    def setUp(self):
+        super(AcceptEncodingTest, self).setUp()
+        self.instance = self.fake.announce(
+            "a",
+            bodies=[gzip.compress(b'{"status": "compressed ok"}')],
+            headers={"Content-Type": "application/json", "Content-Encoding": "gzip"},
+        )
+
+    def test_gzip_requested_and_decoded(self):
+        code, lines = self.run_check(
+            "--accept-encoding", "gzip", "--body-match-on", "200=compressed ok"
+        )
+        self.assertEqual(code, 0)
+        self.assertEqual(self.instance.requests[0].headers["Accept-Encoding"], "gzip")
+
+    def test_undecoded_body_would_not_match(self):
+        code, lines = self.run_check(
+            "--accept-encoding", "gzip", "--body-match-on", "200=^\\x1f\\x8b"
+        )
+        self.assertNotEqual(code, 0)
+
+    def test_identity(self):
+        self.instance.bodies = [b'{"status": "plain ok"}']
+        del self.instance.headers["Content-Encoding"]
+        code, lines = self.run_check("--accept-encoding", "identity")
+        self.assertEqual(code, 0)
+        headers = self.instance.requests[0].headers
+        self.assertEqual(headers["Accept-Encoding"], "identity")
+
+
+if __name__ == "__main__":
+    unittest.main()
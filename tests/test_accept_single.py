@@ -0,0 +1,47 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class AcceptSingleTest(CheckTestCase):
+    def setUp(self):
+        super(AcceptSingleTest, self).setUp()
+        self.instance = self.fake.announce("a")
+
+    def test_array(self):
+        for args in ((), ("--accept-single",)):
+            code, lines = self.run_check(*args)
+            self.assertEqual(code, 0)
+        self.assertEqual(self.instance.probes, 2)
+
+    def test_object_rejected(self):
+        self.fake.state_wrap = lambda anns: anns[0]
+        code, lines = self.run_check()
+        self.assertEqual(code, 3)
+        self.assertEqual(lines[0], "failed to get announcements")
+        self.assertLine(lines, "discovery state is an object, not an array")
+        self.assertEqual(self.instance.probes, 0)
+
+    def test_object_accepted(self):
+        self.fake.state_wrap = lambda anns: anns[0]
+        code, lines = self.run_check("--accept-single")
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "announcements ok: 1")
+        self.assertEqual(self.instance.probes, 1)
+
+    def test_neither(self):
+        self.fake.state_wrap = lambda anns: "nope"
+        code, lines = self.run_check("--accept-single")
+        self.assertEqual(code, 3)
+        self.assertLine(lines, "discovery state is not an array: 'nope'")
+
+    def test_object_in_envelope(self):
+        self.fake.state_wrap = lambda anns: {"items": anns[0]}
+        code, lines = self.run_check(
+            "--state-envelope-field", "items", "--accept-single"
+        )
+        self.assertEqual(code, 0)
+
+
+if __name__ == "__main__":
+    unittest.main()
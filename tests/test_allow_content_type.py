@@ -0,0 +1,44 @@
+import unittest
+
+from helpers import CheckTestCase
+
+ALLOWED = [
+    "--allow-content-type",
+    "application/json",
+    "--allow-content-type",
+    "application/health+json",
+]
+
+
+def content_type(value):
+    return {"Content-Type": value}
+
+
+class AllowContentTypeTest(CheckTestCase):
+    def test_allowed(self):
+        self.fake.announce("a")
+        self.fake.announce("b", headers=content_type("application/health+json"))
+        self.fake.announce("c", headers=content_type("Application/JSON; charset=utf-8"))
+        code, lines = self.run_check(*ALLOWED)
+        self.assertEqual(code, 0)
+
+    def test_disallowed(self):
+        self.fake.announce("a", headers=content_type("text/html; charset=utf-8"))
+        code, lines = self.run_check(*ALLOWED)
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "content type text/html not allowed")
+
+    def test_missing(self):
+        self.fake.announce("a", bodies=(b"OK",))
+        code, lines = self.run_check(*ALLOWED)
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "content type missing not allowed")
+
+    def test_any_without_flag(self):
+        self.fake.announce("a", headers=content_type("text/html"))
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,36 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class AllowedEnvironmentsTest(CheckTestCase):
+    def setUp(self):
+        super(AllowedEnvironmentsTest, self).setUp()
+        self.fake.announce("a")
+        self.fake.announce("b", environment="prod-east")
+
+    def test_all_allowed(self):
+        code, lines = self.run_check("--allowed-environments", "prod,prod-east")
+        self.assertEqual(code, 0)
+        self.assertNotIn("environments warning", "\n".join(lines))
+
+    def test_unexpected_environment_warns(self):
+        self.fake.announce("leak", environment="staging")
+        code, lines = self.run_check("--allowed-environments", "prod,prod-east")
+        self.assertEqual(code, 1)
+        self.assertLine(
+            lines, "environments warning: 1 announcements outside prod,prod-east"
+        )
+        self.assertLine(lines, "staging svc %s" % self.fake.uri("leak"))
+
+    def test_offenders_filtered_by_environment_still_warn(self):
+        self.fake.announce("leak", environment="staging")
+        code, lines = self.run_check("-E", "prod", "--allowed-environments", "prod")
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "2 announcements outside prod")
+        # Only the prod instance is health checked.
+        self.assertEqual(self.fake.probes(), {"a": 1, "b": 0, "leak": 0})
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,45 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class AuthFailureStatusTest(CheckTestCase):
+    def test_401_under_each_status(self):
+        self.fake.announce("a", statuses=(401,))
+        for status, expected in (
+            ("ok", 0),
+            ("warning", 1),
+            ("critical", 2),
+            ("unknown", 3),
+        ):
+            code, lines = self.run_check("--auth-failure-status", status)
+            self.assertEqual(code, expected, status)
+            self.assertLine(lines, "authentication failed")
+
+    def test_default_warning(self):
+        self.fake.announce("a", statuses=(401,))
+        self.fake.announce("b", statuses=(403,))
+        code, lines = self.run_check()
+        self.assertEqual(code, 1)
+
+    def test_403(self):
+        self.fake.announce("a", statuses=(403,))
+        code, lines = self.run_check("--auth-failure-status", "unknown")
+        self.assertEqual(code, 3)
+
+    def test_other_4xx_unaffected(self):
+        self.fake.announce("a", statuses=(404,))
+        code, lines = self.run_check("--auth-failure-status", "unknown")
+        self.assertEqual(code, 1)
+        self.assertFalse(any("authentication failed" in line for line in lines))
+
+    def test_bad_status(self):
+        self.assertParserError(
+            ["--auth-failure-status", "fatal"],
+            "expected one of ok, warning, critical, unknown",
+            code=2,
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,32 @@
+import unittest
+
+from helpers import CheckTestCase
+
+MATCH = ["--body-match-on", "200=Café"]
+
+
+class BodyCharsetTest(CheckTestCase):
+    def setUp(self):
+        super(BodyCharsetTest, self).setUp()
+        body = '{"status": "Café"}'.encode("latin-1")
+        self.fake.announce(
+            "a", bodies=(body,), headers={"Content-Type": "application/json"}
+        )
+
+    def test_matches_after_decoding(self):
+        code, lines = self.run_check("--body-charset", "latin-1", *MATCH)
+        self.assertEqual(code, 0)
+
+    def test_mismatch_as_utf8(self):
+        code, lines = self.run_check(*MATCH)
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "body does not match 'Café'")
+
+    def test_unknown_charset(self):
+        self.assertParserError(
+            ["--body-charset", "klingon"], "unknown body-charset klingon"
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
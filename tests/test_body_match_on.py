@@ -0,0 +1,40 @@
+import unittest
+
+from helpers import CheckTestCase
+
+UP = ["--body-match-on", "200=UP"]
+DRAIN = ["--body-match-on", "503=draining"]
+
+
+class BodyMatchOnTest(CheckTestCase):
+    def test_matched_200(self):
+        self.fake.announce("a", bodies=({"status": "UP"},))
+        code, lines = self.run_check(*UP + DRAIN)
+        self.assertEqual(code, 0)
+
+    def test_unmatched_200(self):
+        self.fake.announce("a", bodies=({"status": "DOWN"},))
+        code, lines = self.run_check(*UP + DRAIN)
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "body does not match 'UP'")
+
+    def test_503_with_drain_body(self):
+        self.fake.announce("a", statuses=(503,), bodies=({"status": "draining"},))
+        code, lines = self.run_check(*UP + DRAIN)
+        self.assertEqual(code, 2)
+        self.assertFalse(any("body does not match" in line for line in lines))
+
+    def test_503_without_drain_body(self):
+        self.fake.announce("a", statuses=(503,), bodies=(b"Internal error",))
+        code, lines = self.run_check(*UP + DRAIN)
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "body does not match 'draining'")
+
+    def test_other_status_ignored(self):
+        self.fake.announce("a", statuses=(204,), bodies=(b"",))
+        code, lines = self.run_check(*UP)
+        self.assertFalse(any("body does not match" in line for line in lines))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,36 @@
+import hashlib
+import unittest
+
+from helpers import CheckTestCase
+
+# Not UTF-8, and served as text/plain without a charset, so requests
+# decodes it as ISO-8859-1.
+BODY = "status: ok\r\n  caf\xe9  \n".encode("latin-1")
+
+
+class BodySha256Test(CheckTestCase):
+    def setUp(self):
+        super(BodySha256Test, self).setUp()
+        self.fake.announce("a", bodies=[BODY], headers={"Content-Type": "text/plain"})
+
+    def test_hash_of_bytes_as_received(self):
+        digest = hashlib.sha256(BODY).hexdigest()
+        code, lines = self.run_check("--expect-body-sha256", digest)
+        self.assertEqual(code, 0)
+
+    def test_mismatch_warns(self):
+        digest = hashlib.sha256(BODY + b"!").hexdigest()
+        code, lines = self.run_check("--expect-body-sha256", digest)
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "expected %s" % digest)
+
+    def test_normalized_whitespace(self):
+        digest = hashlib.sha256(b"status: ok caf\xe9").hexdigest()
+        code, lines = self.run_check(
+            "--expect-body-sha256", digest, "--normalize-whitespace"
+        )
+        self.assertEqual(code, 0)
+
+
+if __name__ == "__main__":
+    unittest.main()
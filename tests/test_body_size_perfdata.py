@@ -0,0 +1,44 @@
+import re
+import unittest
+
+from helpers import CheckTestCase
+
+
+def perfdata(lines):
+    return dict(re.findall(r"(\w+)=(\S+)", lines[0].split(" | ", 1)[1]))
+
+
+class BodySizePerfdataTest(CheckTestCase):
+    def test_known_sizes(self):
+        # 10, 20, ... 200 bytes: nearest-rank p95 is the 19th.
+        for n in range(1, 21):
+            self.fake.announce("i%02d" % n, bodies=(b"x" * (10 * n),))
+        code, lines = self.run_check(
+            "--body-size-perfdata", "--annotate-perfdata-with-units"
+        )
+        self.assertEqual(code, 0)
+        perf = perfdata(lines)
+        self.assertEqual(perf["body_size_min"], "10B")
+        self.assertEqual(perf["body_size_mean"], "105B")
+        self.assertEqual(perf["body_size_p95"], "190B")
+        self.assertEqual(perf["body_size_max"], "200B")
+
+    def test_single_instance(self):
+        self.fake.announce("a", bodies=(b"x" * 42,))
+        code, lines = self.run_check("--body-size-perfdata")
+        perf = perfdata(lines)
+        for stat in ("min", "mean", "p95", "max"):
+            self.assertEqual(perf["body_size_" + stat], "42")
+
+    def test_empty_fleet(self):
+        code, lines = self.run_check("--body-size-perfdata", "-c", "0", "-w", "0")
+        self.assertNotIn("body_size", lines[0])
+
+    def test_off_by_default(self):
+        self.fake.announce("a")
+        code, lines = self.run_check()
+        self.assertNotIn("body_size", lines[0])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,30 @@
+import time
+import unittest
+
+from helpers import CheckTestCase
+
+
+class BodyTimeoutTest(CheckTestCase):
+    def test_slow_chunked_body(self):
+        chunks = [(0.3, b" " * 10)] * 10
+        self.fake.announce("a", chunks=[(0, b"{")] + chunks + [(0, b"}")])
+        started = time.time()
+        code, lines = self.run_check("-t", "1")
+        self.assertLess(time.time() - started, 2)
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "body read timeout critical: thresh. 1.000")
+
+    def test_headers_timeout_is_not_a_body_timeout(self):
+        self.fake.announce("a", delay=2)
+        code, lines = self.run_check("-t", "1")
+        self.assertEqual(code, 2)
+        self.assertNotIn("body read", "\n".join(lines))
+
+    def test_quick_chunked_body(self):
+        self.fake.announce("a", chunks=[(0, b'{"status": '), (0.1, b'"ok"}')])
+        code, lines = self.run_check("-t", "1", "--body-match-on", '200="ok"')
+        self.assertEqual(code, 0)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,80 @@
+import socket
+import unittest
+from unittest import mock
+
+from helpers import CheckTestCase
+
+HOST = "dual.test"
+
+
+def stub_resolver(real):
+    # HOST is dual-stack on the loopback addresses; the rest resolve as usual.
+    def getaddrinfo(host, port, family=0, type=0, proto=0, flags=0):
+        if host != HOST:
+            return real(host, port, family, type, proto, flags)
+        infos = []
+        if family in (0, socket.AF_INET):
+            infos.append(
+                (socket.AF_INET, socket.SOCK_STREAM, 6, "", ("127.0.0.1", port))
+            )
+        if family in (0, socket.AF_INET6):
+            infos.append(
+                (socket.AF_INET6, socket.SOCK_STREAM, 6, "", ("::1", port, 0, 0))
+            )
+        if not infos:
+            raise socket.gaierror(socket.EAI_NONAME, "no such host")
+        return infos
+
+    return getaddrinfo
+
+
+class BothFamiliesTest(CheckTestCase):
+    def setUp(self):
+        super(BothFamiliesTest, self).setUp()
+        patcher = mock.patch.object(
+            socket, "getaddrinfo", stub_resolver(socket.getaddrinfo)
+        )
+        patcher.start()
+        self.addCleanup(patcher.stop)
+        port = self.fake.url.rsplit(":", 1)[1].strip("/")
+        uri = "http://%s:%s/a/" % (HOST, port)
+        self.instance = self.fake.announce("a", serviceUri=uri)
+
+    def test_both_families_probed(self):
+        try:
+            self.fake.serve_ipv6()
+        except OSError as e:
+            self.skipTest("no IPv6 loopback: %s" % e)
+        code, lines = self.run_check("--check-both-families")
+        self.assertEqual(code, 0)
+        self.assertIn("ipv4: 1 of 1 addresses ok", lines)
+        self.assertIn("ipv6: 1 of 1 addresses ok", lines)
+        # The usual probe, then one per address.
+        servers = [r.server for r in self.instance.requests]
+        self.assertEqual(sorted(servers[1:]), ["127.0.0.1", "::1"])
+        hosts = [r.headers["Host"] for r in self.instance.requests]
+        self.assertTrue(all(h.startswith(HOST) for h in hosts))
+
+    def test_broken_family_warns(self):
+        # Nothing listens on [::1].
+        code, lines = self.run_check("--check-both-families")
+        self.assertEqual(code, 1)
+        self.assertIn("ipv4: 1 of 1 addresses ok", lines)
+        self.assertIn("ipv6: 0 of 1 addresses ok", lines)
+        self.assertIn("failed: ::1", lines)
+
+    def test_single_family_host(self):
+        self.fake.announce("a")
+        code, lines = self.run_check("--check-both-families")
+        self.assertEqual(code, 0)
+        self.assertIn("ipv4: 1 of 1 addresses ok", lines)
+        self.assertFalse(any(line.startswith("ipv6") for line in lines))
+
+    def test_off_by_default(self):
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+        self.assertEqual(self.instance.probes, 1)
+
+
+if __name__ == "__main__":
+    unittest.main()
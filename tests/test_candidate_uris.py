@@ -0,0 +1,42 @@
+import unittest
+
+from helpers import CheckTestCase
+
+DOWN = "http://127.0.0.1:1/a/"
+
+
+class CandidateUrisTest(CheckTestCase):
+    def test_failing_primary_healthy_secondary(self):
+        self.fake.announce(
+            "a", serviceUri=DOWN, metadata={"uris": [DOWN, self.fake.uri("a")]}
+        )
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "check URI %sa/health" % self.fake.url)
+        self.assertLine(lines, "candidate URI 2 of 2")
+        self.assertEqual(self.fake.probes(), {"a": 1})
+
+    def test_first_healthy_candidate_wins(self):
+        self.fake.announce("a", metadata={"uris": [self.fake.uri("a"), DOWN]})
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "candidate URI 1 of 2")
+
+    def test_all_candidates_failing(self):
+        # Nothing is announced at these paths, so they answer 404.
+        uris = [self.fake.uri("gone1"), self.fake.uri("gone2")]
+        self.fake.announce("a", metadata={"uris": uris})
+        code, lines = self.run_check()
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "check URI %sgone2/health" % self.fake.url)
+        self.assertLine(lines, "candidate URI 2 of 2")
+
+    def test_no_list_uses_service_uri(self):
+        self.fake.announce("a", metadata={"uris": []})
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+        self.assertNotIn("candidate URI", "\n".join(lines))
+
+
+if __name__ == "__main__":
+    unittest.main()
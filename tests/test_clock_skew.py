@@ -0,0 +1,45 @@
+import time
+import unittest
+
+from helpers import CheckTestCase
+
+
+def iso(ts):
+    return time.strftime("%Y-%m-%dT%H:%M:%SZ", time.gmtime(ts))
+
+
+class ClockSkewTest(CheckTestCase):
+    def run_skew(self):
+        return self.run_check("--clock-skew-warn", "30")
+
+    def test_in_sync(self):
+        self.fake.announce("a")
+        self.assertEqual(self.run_skew()[0], 0)
+
+    def test_skewed_date_header(self):
+        self.fake.announce("a")
+        self.fake.clock_offset = -300
+        code, lines = self.run_skew()
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "clock skew -30")
+
+    def test_skewed_server_time(self):
+        self.fake.announce("a", bodies=[{"serverTime": iso(time.time() + 120)}])
+        code, lines = self.run_skew()
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "clock skew 1")
+        self.assertLine(lines, "thresh. 30.000")
+
+    def test_server_time_in_milliseconds(self):
+        ms = int((time.time() + 120) * 1000)
+        self.fake.announce("a", bodies=[{"serverTime": ms}])
+        self.assertEqual(self.run_skew()[0], 1)
+
+    def test_server_time_preferred_over_date(self):
+        self.fake.announce("a", bodies=[{"serverTime": iso(time.time())}])
+        self.fake.clock_offset = -300
+        self.assertEqual(self.run_skew()[0], 0)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,60 @@
+import io
+import os
+import unittest
+from contextlib import redirect_stdout
+from unittest import mock
+
+from helpers import CheckTestCase
+
+ESC = "\033["
+
+
+class Terminal(io.StringIO):
+    def isatty(self):
+        return True
+
+
+class ColorTest(CheckTestCase):
+    def setUp(self):
+        super(ColorTest, self).setUp()
+        self.fake.announce("a", statuses=(500,))
+        self.fake.announce("b")
+        env = dict(os.environ)
+        env.pop("NO_COLOR", None)
+        patcher = mock.patch.dict(os.environ, env, clear=True)
+        patcher.start()
+        self.addCleanup(patcher.stop)
+
+    def run_tty(self, *args):
+        out = Terminal()
+        with redirect_stdout(out):
+            self.main(*args).run()
+        return out.getvalue()
+
+    def test_terminal_colored(self):
+        out = self.run_tty()
+        self.assertIn("\033[31mhealth critical: 500 from endpoint\033[0m", out)
+        self.assertIn("\033[32mhealth ok: 200 from endpoint\033[0m", out)
+
+    def test_piped_plain(self):
+        code, lines = self.run_check()
+        self.assertNotIn(ESC, "\n".join(lines))
+
+    def test_always(self):
+        code, lines = self.run_check("--color")
+        self.assertIn(ESC, "\n".join(lines))
+
+    def test_never(self):
+        self.assertNotIn(ESC, self.run_tty("--no-color"))
+
+    def test_no_color_env(self):
+        os.environ["NO_COLOR"] = "1"
+        self.assertNotIn(ESC, self.run_tty())
+
+    def test_machine_formats_plain(self):
+        for output in ("json", "ndjson", "logfmt", "openmetrics"):
+            self.assertNotIn(ESC, self.run_tty("--color", "--output", output))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,57 @@
+import unittest
+from unittest import mock
+
+from helpers import CheckTestCase, check
+
+
+class ConcurrencyTest(CheckTestCase):
+    def setUp(self):
+        super(ConcurrencyTest, self).setUp()
+        for name in "abcde":
+            self.fake.announce(name)
+
+    def test_unlimited_is_one_process_per_instance(self):
+        probes = self.fake.state()
+        batches = self.main("--concurrency", "0").concurrency_batches(probes)
+        self.assertEqual(batches, [(5, probes)])
+
+    def test_unlimited_is_capped(self):
+        probes = self.fake.state()
+        with mock.patch.object(check, "maxprocesses", 2):
+            batches = self.main("--concurrency", "0").concurrency_batches(probes)
+            self.assertEqual(batches, [(2, probes)])
+            code, lines = self.run_check("--concurrency", "0")
+        self.assertEqual(code, 0)
+        self.assertEqual(self.fake.probes(), dict.fromkeys("abcde", 1))
+
+
+    def test_service_concurrency_is_independent(self):
+        for name in "vwxyz":
+            self.fake.announce(name, delay=0.5, serviceType="big")
+        for name in "abcde":
+            self.fake.instances[name].delay = 0.5
+        args = ["-s", "big", "--concurrency", "3", "--service-concurrency", "big=1"]
+        probes = self.fake.state()
+        batches = self.main(*args).concurrency_batches(probes)
+        self.assertEqual(
+            [(size, [a["serviceType"] for a in batch]) for size, batch in batches],
+            [(1, ["big"] * 5), (3, ["svc"] * 5)],
+        )
+        code, lines = self.run_check(*args)
+        self.assertEqual(code, 0)
+        self.assertEqual(self.fake.max_active["big"], 1)
+        self.assertGreater(self.fake.max_active["svc"], 1)
+        self.assertLessEqual(self.fake.max_active["svc"], 3)
+
+    def test_unlisted_services_use_concurrency(self):
+        for name in "vw":
+            self.fake.announce(name, serviceType="big")
+        probes = self.fake.state()
+        main = self.main(
+            "-s", "big", "--concurrency", "4", "--service-concurrency", "other=1"
+        )
+        self.assertEqual(main.concurrency_batches(probes), [(4, probes)])
+
+
+if __name__ == "__main__":
+    unittest.main()
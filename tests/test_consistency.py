@@ -0,0 +1,44 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class ConsistencyTest(CheckTestCase):
+    def test_differing_bodies_warn(self):
+        for name in "abc":
+            self.fake.announce(name, bodies=({"config": "v1"},))
+        self.fake.announce("d", bodies=({"config": "v2"},))
+        code, lines = self.run_check("--consistency-check")
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "consistency warning: 2 distinct health responses")
+        variants = [line for line in lines if " instances, e.g. " in line]
+        self.assertEqual(len(variants), 2)
+        # Most common first; the example is whichever instance answered first.
+        self.assertIn(": 3 instances, e.g. ", variants[0])
+        examples = ["%shealth" % self.fake.uri(name) for name in "abc"]
+        self.assertIn(variants[0].rsplit(" ", 1)[1], examples)
+        self.assertIn(": 1 instances, e.g. %shealth" % self.fake.uri("d"), variants[1])
+
+    def test_identical_bodies_ok(self):
+        for name in "abc":
+            self.fake.announce(name, bodies=({"config": "v1"},))
+        code, lines = self.run_check("--consistency-check")
+        self.assertEqual(code, 0)
+        self.assertFalse(any("consistency" in line for line in lines))
+
+    def test_failing_instances_not_compared(self):
+        self.fake.announce("a", bodies=({"config": "v1"},))
+        self.fake.announce("b", statuses=(500,), bodies=({"error": "boom"},))
+        code, lines = self.run_check("--consistency-check")
+        self.assertEqual(code, 2)
+        self.assertFalse(any("consistency" in line for line in lines))
+
+    def test_off_by_default(self):
+        self.fake.announce("a", bodies=({"config": "v1"},))
+        self.fake.announce("b", bodies=({"config": "v2"},))
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+
+
+if __name__ == "__main__":
+    unittest.main()
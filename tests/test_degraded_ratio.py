@@ -0,0 +1,47 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class DegradedRatioTest(CheckTestCase):
+    def setUp(self):
+        super(DegradedRatioTest, self).setUp()
+        # Empty bodies warn with --require-body-on-2xx.
+        for name in "ab":
+            self.fake.announce(name, bodies=(b"",))
+        for name in "cd":
+            self.fake.announce(name)
+
+    def run_ratio(self, ratio):
+        return self.run_check("--require-body-on-2xx", "--degraded-ratio-crit", ratio)
+
+    def test_enough_warnings_escalate(self):
+        code, lines = self.run_ratio("0.4")
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "degraded critical: 2 of 4 health results are warnings")
+        self.assertLine(lines, "thresh. 40%")
+
+    def test_ratio_must_be_exceeded(self):
+        code, lines = self.run_ratio("0.5")
+        self.assertEqual(code, 1)
+        self.assertFalse(any(line.startswith("degraded") for line in lines))
+
+    def test_criticals_are_not_degraded(self):
+        self.fake.instances["a"].statuses = [500]
+        self.fake.instances["b"].statuses = [500]
+        code, lines = self.run_ratio("0")
+        self.assertEqual(code, 2)
+        self.assertFalse(any(line.startswith("degraded") for line in lines))
+
+    def test_off_by_default(self):
+        code, lines = self.run_check("--require-body-on-2xx")
+        self.assertEqual(code, 1)
+
+    def test_ratio_range(self):
+        self.assertParserError(
+            ["--degraded-ratio-crit", "1"], "degraded-ratio-crit must be in [0, 1)"
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,56 @@
+import time
+import unittest
+
+from helpers import CheckTestCase
+
+
+def deployed(seconds_ago):
+    return {"deploy-time": time.time() - seconds_ago}
+
+
+class DeployGraceTest(CheckTestCase):
+    def test_recent_deploy_metadata_caps_criticals(self):
+        self.fake.announce("a", statuses=(500,), metadata=deployed(10))
+        self.fake.announce("b")
+        code, lines = self.run_check("--deploy-grace", "300")
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "(deploy grace)")
+
+    def test_iso_deploy_time(self):
+        stamp = time.strftime("%Y-%m-%dT%H:%M:%SZ", time.gmtime(time.time() - 10))
+        self.fake.announce("a", statuses=(500,), metadata={"deploy-time": stamp})
+        code, lines = self.run_check("--deploy-grace", "300")
+        self.assertEqual(code, 1)
+
+    def test_old_deploy_stays_critical(self):
+        self.fake.announce("a", statuses=(500,), metadata=deployed(3600))
+        code, lines = self.run_check("--deploy-grace", "300")
+        self.assertEqual(code, 2)
+        self.assertFalse(any("(deploy grace)" in line for line in lines))
+
+    def test_no_deploy_time_stays_critical(self):
+        self.fake.announce("a", statuses=(500,))
+        code, lines = self.run_check("--deploy-grace", "300")
+        self.assertEqual(code, 2)
+
+    def test_marker_sees_new_announcement(self):
+        marker = ["--deploy-grace", "300", "--deploy-marker", self.path("deploy")]
+        self.fake.announce("a")
+        code, lines = self.run_check(*marker)
+        self.assertEqual(code, 0)
+        # A new instance appears, failing: a deploy in progress.
+        self.fake.announce("b", statuses=(500,))
+        code, lines = self.run_check(*marker)
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "(deploy grace)")
+
+    def test_marker_baseline_is_not_a_deploy(self):
+        self.fake.announce("a", statuses=(500,))
+        code, lines = self.run_check(
+            "--deploy-grace", "300", "--deploy-marker", self.path("deploy")
+        )
+        self.assertEqual(code, 2)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,40 @@
+import os
+import unittest
+
+from helpers import TLS, CheckTestCase
+
+
+def address(url):
+    return url.split("//")[1].rstrip("/")
+
+
+class DialViaTest(CheckTestCase):
+    def test_dials_address_keeping_host(self):
+        # Nothing resolves or listens at the announced address.
+        a = self.fake.announce("a", serviceUri="http://example.invalid:8080/a/")
+        code, lines = self.run_check("--dial-via", address(self.fake.url))
+        self.assertEqual(code, 0)
+        self.assertEqual(a.requests[0].path, "/a/health")
+        self.assertEqual(a.requests[0].headers["Host"], "example.invalid:8080")
+
+    def test_tls_verifies_announced_host(self):
+        self.fake.serve_tls()
+        a = self.fake.announce("a", serviceUri="https://localhost:1/a/")
+        code, lines = self.run_check(
+            "--dial-via",
+            address(self.fake.tls_url),
+            "--ca-file",
+            os.path.join(TLS, "ca.pem"),
+        )
+        self.assertEqual(code, 0)
+        self.assertEqual(a.requests[0].headers["Host"], "localhost:1")
+
+    def test_conflicts_with_socks5(self):
+        self.assertParserError(
+            ["--dial-via", "127.0.0.1:1", "--socks5", "127.0.0.1:2"],
+            "dial-via conflicts with socks5",
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
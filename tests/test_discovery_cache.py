@@ -0,0 +1,54 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class DiscoveryCacheTest(CheckTestCase):
+    def setUp(self):
+        super(DiscoveryCacheTest, self).setUp()
+        self.fake.announce("a")
+
+    def run_cached(self, *args):
+        return self.run_check("--discovery-cache", self.path("cache"), *args)
+
+    def test_second_run_uses_cache(self):
+        code, lines = self.run_cached()
+        self.assertEqual(code, 0)
+        self.assertNotIn("discovery_cache_age", lines[0])
+        self.assertEqual(self.fake.state_requests, 1)
+        code, lines = self.run_cached()
+        self.assertEqual(code, 0)
+        self.assertIn("discovery_cache_age=", lines[0])
+        self.assertEqual(self.fake.state_requests, 1)
+
+    def test_recheck_skips_cache(self):
+        self.fake.announce("b", statuses=[500])
+        self.run_cached()
+        code, lines = self.run_cached()
+        self.assertEqual(code, 2)
+        # One fetch to fill the cache, and one re-check before each page.
+        self.assertEqual(self.fake.state_requests, 3)
+        self.assertEqual(lines[0].count("discovery_cache_age="), 1)
+
+    def test_cache_age_reported_once_per_run(self):
+        self.run_cached("--discovery-query-service", "-s", "other")
+        code, lines = self.run_cached("--discovery-query-service", "-s", "other")
+        self.assertEqual(lines[0].count("discovery_cache_age="), 1)
+
+    def test_stale_cache_is_refreshed(self):
+        self.run_cached("--cache-ttl", "0.001")
+        self.run_cached("--cache-ttl", "0.001")
+        self.assertEqual(self.fake.state_requests, 2)
+
+    def test_jittered_ttl_stays_in_range(self):
+        path = self.path("cache")
+        ttls = [
+            self.main("--discovery-cache", path, "--cache-jitter", "2").cache_ttl
+            for _ in range(50)
+        ]
+        self.assertTrue(all(8 <= ttl <= 12 for ttl in ttls), ttls)
+        self.assertGreater(len(set(ttls)), 1)
+
+
+if __name__ == "__main__":
+    unittest.main()
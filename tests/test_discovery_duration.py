@@ -0,0 +1,35 @@
+import re
+import unittest
+
+from helpers import CheckTestCase
+
+
+class DiscoveryDurationTest(CheckTestCase):
+    def duration(self, lines):
+        match = re.search(r" \| discovery_duration=(\d+\.\d{3}) ", lines[0])
+        self.assertIsNotNone(match, lines[0])
+        return float(match.group(1))
+
+    def test_always_emitted(self):
+        self.fake.announce("a")
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+        self.assertLess(self.duration(lines), 1)
+
+    def test_slow_discovery(self):
+        self.fake.announce("a")
+        self.fake.state_delay = 0.3
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+        duration = self.duration(lines)
+        self.assertGreaterEqual(duration, 0.3)
+        self.assertLess(duration, 2)
+
+    def test_emitted_without_announcements(self):
+        code, lines = self.run_check()
+        self.assertEqual(code, 2)
+        self.duration(lines)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,48 @@
+import unittest
+
+from helpers import CheckTestCase
+
+SERVICES = ["-s", "other", "--discovery-query-service"]
+
+
+class DiscoveryQueryServiceTest(CheckTestCase):
+    def setUp(self):
+        super(DiscoveryQueryServiceTest, self).setUp()
+        self.fake.announce("a")
+        self.fake.announce("b", serviceType="other")
+        self.fake.announce("c", serviceType="unrelated")
+
+    def state_paths(self):
+        return [path for path, _ in self.fake.state_log]
+
+    def test_query_honored(self):
+        self.fake.service_query = True
+        code, lines = self.run_check(*SERVICES)
+        self.assertEqual(code, 0)
+        self.assertEqual(
+            self.state_paths(), ["/state?service=svc", "/state?service=other"]
+        )
+        self.assertEqual(self.fake.probes(), {"a": 1, "b": 1, "c": 0})
+
+    def test_query_ignored_falls_back_to_filtering(self):
+        code, lines = self.run_check(*SERVICES)
+        self.assertEqual(code, 0)
+        # The first answer was the full state, so there's no need to ask again.
+        self.assertEqual(self.state_paths(), ["/state?service=svc"])
+        self.assertEqual(self.fake.probes(), {"a": 1, "b": 1, "c": 0})
+
+    def test_query_escaped(self):
+        self.fake.service_query = True
+        self.fake.announce("d", serviceType="a b&c")
+        code, lines = self.run_check("--discovery-query-service", "-s", "a b&c")
+        self.assertIn("/state?service=a+b%26c", self.state_paths())
+        self.assertEqual(self.fake.instances["d"].probes, 1)
+
+    def test_off_by_default(self):
+        code, lines = self.run_check("-s", "other")
+        self.assertEqual(self.state_paths(), ["/state"])
+        self.assertEqual(self.fake.probes(), {"a": 1, "b": 1, "c": 0})
+
+
+if __name__ == "__main__":
+    unittest.main()
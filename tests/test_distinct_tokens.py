@@ -0,0 +1,33 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class DistinctTokensTest(CheckTestCase):
+    def announce(self, tokens):
+        for i, token in enumerate(tokens):
+            self.fake.announce("i%s" % i, metadata={"server-token": token})
+
+    def test_one_shared_token(self):
+        self.announce(["host1"] * 5)
+        code, lines = self.run_check(
+            "--min-distinct-tokens-warn", "3", "--min-distinct-tokens-crit", "2"
+        )
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "tokens critical: 1 distinct server-token")
+        self.assertIn("tokens=1", lines[0])
+
+    def test_warning(self):
+        self.announce(["host1", "host2", "host2", "", None])
+        code, lines = self.run_check("--min-distinct-tokens-warn", "3")
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "tokens warning: 2 distinct server-token")
+
+    def test_enough_tokens(self):
+        self.announce(["host1", "host2", "host3"])
+        code, lines = self.run_check("--min-distinct-tokens-warn", "3")
+        self.assertEqual(code, 0)
+
+
+if __name__ == "__main__":
+    unittest.main()
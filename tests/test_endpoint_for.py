@@ -0,0 +1,56 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class EndpointForTest(CheckTestCase):
+    def setUp(self):
+        super(EndpointForTest, self).setUp()
+        self.svc = self.fake.announce("a")
+        self.other = self.fake.announce("b", serviceType="other")
+        self.third = self.fake.announce("c", serviceType="third")
+
+    def paths(self, instance):
+        return sorted(r.path for r in instance.requests)
+
+    def test_two_services_two_paths(self):
+        code, lines = self.run_check(
+            "-s",
+            "other",
+            "--endpoint-for",
+            "svc=status",
+            "--endpoint-for",
+            "other=actuator/health",
+        )
+        self.assertEqual(code, 0)
+        self.assertEqual(self.paths(self.svc), ["/a/status"])
+        self.assertEqual(self.paths(self.other), ["/b/actuator/health"])
+
+    def test_falls_back_to_endpoint(self):
+        code, lines = self.run_check(
+            "-s", "other", "-s", "third", "-e", "ping", "--endpoint-for", "other=ready"
+        )
+        self.assertEqual(code, 0)
+        self.assertEqual(self.paths(self.svc), ["/a/ping"])
+        self.assertEqual(self.paths(self.other), ["/b/ready"])
+        self.assertEqual(self.paths(self.third), ["/c/ping"])
+
+    def test_repeated_for_one_service(self):
+        code, lines = self.run_check(
+            "--endpoint-for", "svc=live", "--endpoint-for", "svc=ready"
+        )
+        self.assertEqual(code, 0)
+        self.assertEqual(self.paths(self.svc), ["/a/live", "/a/ready"])
+
+    def test_failure_reported_per_service_path(self):
+        self.other.routes = {"actuator/health": 500}
+        code, lines = self.run_check(
+            "-s", "other", "--endpoint-for", "other=actuator/health"
+        )
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "check URI %sactuator/health" % self.fake.uri("b"))
+        self.assertEqual(self.paths(self.svc), ["/a/health"])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,38 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class ExcludeHostTest(CheckTestCase):
+    def setUp(self):
+        super(ExcludeHostTest, self).setUp()
+        self.fake.announce("a")
+        self.fake.announce("b")
+        bad = self.fake.uri("bad").replace("127.0.0.1", "localhost")
+        self.fake.announce("bad", statuses=[500], serviceUri=bad)
+
+    def test_excluded_host_absent(self):
+        code, lines = self.run_check("--exclude-host", "localhost")
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "announcements ok: 2")
+        self.assertLine(lines, "excluded ok: 1 announcements on localhost")
+        self.assertNotIn("/bad/", "\n".join(lines))
+        self.assertIn(" instances=2 ", lines[0])
+        self.assertEqual(self.fake.probes(), {"a": 1, "b": 1, "bad": 0})
+
+    def test_exclusion_can_leave_too_few(self):
+        code, lines = self.run_check(
+            "--exclude-host", "localhost", "--exclude-host", "127.0.0.1"
+        )
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "announcements critical: 0")
+        self.assertLine(lines, "3 announcements on localhost,127.0.0.1")
+
+    def test_without_flag(self):
+        code, lines = self.run_check()
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "announcements ok: 3")
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,55 @@
+import re
+import unittest
+
+from helpers import CheckTestCase
+
+EXEMPLAR = re.compile(
+    r'^otpl_health_duration_seconds_bucket\{(.*),le="([^"]+)"\} 1 '
+    r'# \{trace_id="([0-9a-f]{32})"\} (\d+\.\d{6})$'
+)
+
+
+class ExemplarTest(CheckTestCase):
+    def setUp(self):
+        super(ExemplarTest, self).setUp()
+        self.instances = [self.fake.announce(name) for name in "ab"]
+
+    def test_exemplar_per_instance(self):
+        code, lines = self.run_check(
+            "--output", "openmetrics", "--trace-id-header", "traceparent"
+        )
+        self.assertEqual(code, 0)
+        self.assertEqual(lines[-1], "# EOF")
+        exemplars = [EXEMPLAR.match(line) for line in lines]
+        exemplars = [m for m in exemplars if m is not None]
+        self.assertEqual(len(exemplars), 2)
+        sent = set()
+        for instance in self.instances:
+            traceparent = instance.requests[0].headers["traceparent"]
+            sent.add(traceparent.split("-")[1])
+        self.assertEqual(set(m.group(3) for m in exemplars), sent)
+        for m in exemplars:
+            # On the lowest bucket the observation falls in.
+            labels, le, _, duration = m.groups()
+            buckets = [
+                line
+                for line in lines
+                if line.startswith("otpl_health_duration_seconds_bucket{%s," % labels)
+            ]
+            first = next(line for line in buckets if "} 1" in line)
+            self.assertIn('le="%s"} 1 #' % le, first)
+            self.assertTrue(le == "+Inf" or float(duration) <= float(le))
+
+    def test_no_exemplars_without_trace_id(self):
+        code, lines = self.run_check("--output", "openmetrics")
+        self.assertEqual(code, 0)
+        self.assertFalse(any(" # {" in line for line in lines))
+        self.assertIn(
+            'otpl_health_duration_seconds_count{service="svc",endpoint="health",'
+            'uri="%shealth"} 1' % self.fake.uri("a"),
+            lines,
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,38 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class ExpectHeaderTest(CheckTestCase):
+    def setUp(self):
+        super(ExpectHeaderTest, self).setUp()
+        self.fake.announce("a", headers={"X-Build": "abc123"})
+
+    def test_match(self):
+        code, lines = self.run_check("--expect-header", "X-Build: abc123")
+        self.assertEqual(code, 0)
+
+    def test_match_ignores_name_case(self):
+        code, lines = self.run_check("--expect-header", "x-build: abc123")
+        self.assertEqual(code, 0)
+
+    def test_mismatch(self):
+        code, lines = self.run_check("--expect-header", "X-Build: def456")
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "header X-Build: 'abc123', expected 'def456'")
+
+    def test_missing(self):
+        code, lines = self.run_check(
+            "--expect-header", "X-Build: abc123", "--expect-header", "X-Region: eu"
+        )
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "missing header X-Region")
+
+    def test_malformed_flag(self):
+        self.assertParserError(
+            ["--expect-header", "X-Build"], "invalid header format: X-Build", code=2
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
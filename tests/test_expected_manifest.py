@@ -0,0 +1,55 @@
+import json
+import unittest
+
+from helpers import CheckTestCase
+
+
+class ExpectedManifestTest(CheckTestCase):
+    def manifest(self, doc):
+        path = self.path("manifest.json")
+        with open(path, "w") as f:
+            json.dump(doc, f)
+        return ["--expected-manifest", path]
+
+    def announce_tokens(self, *tokens):
+        for token in tokens:
+            self.fake.announce(token, metadata={"server-token": token})
+
+    def test_mismatch(self):
+        self.announce_tokens("tok-a", "tok-b", "tok-x", "tok-y")
+        args = self.manifest({"svc": ["tok-a", "tok-b", "tok-c", "tok-d"]})
+        code, lines = self.run_check(*args)
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "svc manifest warning: 2 missing, 2 unexpected")
+        self.assertIn("missing: tok-c, tok-d", lines)
+        self.assertIn("unexpected: tok-x, tok-y", lines)
+
+    def test_match(self):
+        self.announce_tokens("tok-a", "tok-b")
+        code, lines = self.run_check(*self.manifest({"svc": ["tok-b", "tok-a"]}))
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "svc manifest ok: as expected")
+
+    def test_host_names(self):
+        port = self.fake.url.rsplit(":", 1)[1].strip("/")
+        uri = "http://localhost:%s/a/" % port
+        self.fake.announce("a", serviceUri=uri)
+        code, lines = self.run_check(*self.manifest({"svc": ["localhost"]}))
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "svc manifest ok: as expected")
+
+    def test_service_not_in_manifest(self):
+        self.announce_tokens("tok-a")
+        code, lines = self.run_check(*self.manifest({"other": ["tok-a"]}))
+        self.assertEqual(code, 1)
+        self.assertIn("unexpected: tok-a", lines)
+
+    def test_unreadable_manifest(self):
+        self.assertParserError(
+            self.manifest(["tok-a"]),
+            "cannot read expected manifest: expected an object of service names",
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
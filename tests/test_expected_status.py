@@ -0,0 +1,44 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class ExpectedStatusTest(CheckTestCase):
+    def test_expected_418_is_ok(self):
+        self.fake.announce("a", statuses=(418,), metadata={"expectHealthStatus": 418})
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "expected per expectHealthStatus")
+
+    def test_string_metadata(self):
+        self.fake.announce("a", statuses=(418,), metadata={"expectHealthStatus": "418"})
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+
+    def test_unexpected_418(self):
+        self.fake.announce("a", statuses=(418,))
+        code, lines = self.run_check()
+        self.assertNotEqual(code, 0)
+
+    def test_other_status_falls_back(self):
+        self.fake.announce("a", statuses=(503,), metadata={"expectHealthStatus": 418})
+        self.fake.announce("b", metadata={"expectHealthStatus": 418})
+        code, lines = self.run_check()
+        self.assertEqual(code, 2)
+        self.assertEqual(
+            [line.split(" | ")[0] for line in lines if line.startswith("health ")],
+            ["health critical: 503 from endpoint", "health ok: 200 from endpoint"],
+        )
+
+    def test_invalid_metadata_ignored(self):
+        for name, value in (("a", "teapot"), ("b", 418.5), ("c", 999)):
+            self.fake.announce(
+                name, statuses=(418,), metadata={"expectHealthStatus": value}
+            )
+        code, lines = self.run_check()
+        self.assertNotEqual(code, 0)
+        self.assertFalse(any("expectHealthStatus" in line for line in lines))
+
+
+if __name__ == "__main__":
+    unittest.main()
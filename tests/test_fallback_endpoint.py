@@ -0,0 +1,44 @@
+import unittest
+
+from helpers import CheckTestCase
+
+FALLBACK = ["--fallback-endpoint", "actuator/health"]
+
+
+class FallbackEndpointTest(CheckTestCase):
+    def test_primary_404_fallback_ok(self):
+        a = self.fake.announce("a", routes={"health": 404, "actuator/health": 200})
+        code, lines = self.run_check(*FALLBACK)
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "primary endpoint 404, used fallback")
+        self.assertEqual(
+            [r.path for r in a.requests], ["/a/health", "/a/actuator/health"]
+        )
+
+    def test_primary_ok_skips_fallback(self):
+        a = self.fake.announce("a", routes={"actuator/health": 500})
+        code, lines = self.run_check(*FALLBACK)
+        self.assertEqual(code, 0)
+        self.assertEqual([r.path for r in a.requests], ["/a/health"])
+
+    def test_only_404_falls_back(self):
+        a = self.fake.announce("a", routes={"health": 503, "actuator/health": 200})
+        code, lines = self.run_check(*FALLBACK)
+        self.assertEqual(code, 2)
+        self.assertEqual([r.path for r in a.requests], ["/a/health"])
+
+    def test_failing_fallback(self):
+        self.fake.announce("a", routes={"health": 404, "actuator/health": 500})
+        code, lines = self.run_check(*FALLBACK)
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "primary endpoint 404, used fallback")
+
+    def test_no_fallback_without_flag(self):
+        a = self.fake.announce("a", routes={"health": 404, "actuator/health": 200})
+        code, lines = self.run_check()
+        self.assertNotEqual(code, 0)
+        self.assertEqual([r.path for r in a.requests], ["/a/health"])
+
+
+if __name__ == "__main__":
+    unittest.main()
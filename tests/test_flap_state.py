@@ -0,0 +1,39 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class FlapStateTest(CheckTestCase):
+    def run_flap(self, status):
+        self.fake.announce("a", statuses=[status])
+        return self.run_check(
+            "--flap-state",
+            self.path("flap"),
+            "--flap-history",
+            "5",
+            "--flap-threshold",
+            "2",
+        )
+
+    def test_alternating_status_warns(self):
+        for status in (500, 200, 500):
+            code, lines = self.run_flap(status)
+            self.assertNotIn("flapping", "\n".join(lines))
+        code, lines = self.run_flap(200)
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "svc changed status 3 times in the last 4 runs")
+
+    def test_steady_status_does_not_warn(self):
+        for status in (500, 500, 500, 500):
+            code, lines = self.run_flap(status)
+        self.assertNotIn("flapping", "\n".join(lines))
+
+    def test_history_is_bounded(self):
+        for status in (200, 500, 200, 500, 500, 500, 500, 500):
+            code, lines = self.run_flap(status)
+        # The early changes have aged out of the five kept runs.
+        self.assertNotIn("flapping", "\n".join(lines))
+
+
+if __name__ == "__main__":
+    unittest.main()
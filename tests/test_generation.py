@@ -0,0 +1,39 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class GenerationTest(CheckTestCase):
+    def setUp(self):
+        super(GenerationTest, self).setUp()
+        self.fake.announce("a")
+
+    def test_header_generation(self):
+        self.fake.state_headers = {"X-Discovery-Generation": "42"}
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "disco generation: 42")
+        self.assertIn(" generation=42 ", lines[0])
+
+    def test_envelope_generation_wins(self):
+        self.fake.state_headers = {"X-Discovery-Generation": "42"}
+        self.fake.state_wrap = lambda anns: {"items": anns, "generation": 43}
+        code, lines = self.run_check("--state-envelope-field", "items")
+        self.assertLine(lines, "disco generation: 43")
+        self.assertIn(" generation=43 ", lines[0])
+
+    def test_non_numeric_generation(self):
+        # Still shown, but not a counter for the perf data.
+        self.fake.state_headers = {"X-Discovery-Generation": "2024-06-a"}
+        code, lines = self.run_check()
+        self.assertLine(lines, "disco generation: 2024-06-a")
+        self.assertNotIn("generation=", lines[0])
+
+    def test_no_generation(self):
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+        self.assertNotIn("generation", "\n".join(lines))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,68 @@
+import hashlib
+import hmac
+import time
+import unittest
+
+from helpers import CheckTestCase
+
+KEY = b"gateway secret"
+
+
+def gateway(algorithm="sha256", header="X-Signature", timestamp="X-Timestamp"):
+    # Accepts only requests signed the way the gateway expects.
+    def health(request):
+        stamp = request.headers.get(timestamp, "")
+        if not stamp.isdigit() or abs(time.time() - int(stamp)) > 30:
+            return 401, {"error": "bad timestamp"}
+        payload = "\n".join(["GET", request.path, stamp]).encode("utf-8")
+        expected = hmac.new(KEY, payload, getattr(hashlib, algorithm)).hexdigest()
+        if not hmac.compare_digest(request.headers.get(header, ""), expected):
+            return 401, {"error": "bad signature"}
+        return 200, None
+
+    return {"health": health}
+
+
+class HmacTest(CheckTestCase):
+    def test_valid_signature(self):
+        a = self.fake.announce("a", routes=gateway())
+        code, lines = self.run_check("--hmac-key", KEY.decode("utf-8"))
+        self.assertEqual(code, 0)
+        self.assertEqual(a.probes, 1)
+
+    def test_configured_scheme(self):
+        self.fake.announce("a", routes=gateway("sha512", "X-Gw-Sig", "X-Gw-Time"))
+        code, lines = self.run_check(
+            "--hmac-key",
+            KEY.decode("utf-8"),
+            "--hmac-algorithm",
+            "sha512",
+            "--hmac-header",
+            "X-Gw-Sig",
+            "--hmac-timestamp-header",
+            "X-Gw-Time",
+        )
+        self.assertEqual(code, 0)
+
+    def test_query_string_signed(self):
+        a = self.fake.announce("a", routes=gateway())
+        code, lines = self.run_check(
+            "--hmac-key", KEY.decode("utf-8"), "-e", "health?deep=1"
+        )
+        self.assertEqual(code, 0)
+        self.assertEqual(a.requests[0].path, "/a/health?deep=1")
+
+    def test_wrong_key_rejected(self):
+        self.fake.announce("a", routes=gateway())
+        code, lines = self.run_check("--hmac-key", "wrong")
+        self.assertNotEqual(code, 0)
+        self.assertLine(lines, "401")
+
+    def test_unsigned_rejected(self):
+        self.fake.announce("a", routes=gateway())
+        code, lines = self.run_check()
+        self.assertNotEqual(code, 0)
+
+
+if __name__ == "__main__":
+    unittest.main()
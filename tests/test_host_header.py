@@ -0,0 +1,26 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class HostHeaderTest(CheckTestCase):
+    def setUp(self):
+        super(HostHeaderTest, self).setUp()
+        self.instance = self.fake.announce("a")
+
+    def test_overridden_host(self):
+        code, lines = self.run_check("--host-header", "store.example.com")
+        self.assertEqual(code, 0)
+        # Still connected to the announced address.
+        self.assertEqual(self.instance.probes, 1)
+        self.assertEqual(self.instance.requests[0].headers["Host"], "store.example.com")
+        self.assertLine(lines, "check URI %sa/health" % self.fake.url)
+
+    def test_default_host(self):
+        self.run_check()
+        host = self.fake.url.split("//")[1].rstrip("/")
+        self.assertEqual(self.instance.requests[0].headers["Host"], host)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,39 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class Http10Test(CheckTestCase):
+    def setUp(self):
+        super(Http10Test, self).setUp()
+        self.fake.only_http10 = True
+        self.instance = self.fake.announce("a")
+
+    def test_http10_server(self):
+        code, lines = self.run_check("--http10")
+        self.assertEqual(code, 0)
+        request = self.instance.requests[0]
+        self.assertEqual(request.version, "HTTP/1.0")
+        self.assertEqual(request.headers["Connection"], "close")
+
+    def test_http11_rejected(self):
+        code, lines = self.run_check()
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "health critical: 505")
+        self.assertEqual(self.instance.requests[0].version, "HTTP/1.1")
+
+    def test_switch_stays_in_pool_workers(self):
+        # HTTP/1.0 is set process wide, but only in the workers that probe.
+        self.run_check("--http10")
+        self.run_check()
+        self.assertEqual(self.instance.requests[1].version, "HTTP/1.1")
+
+    def test_conflicts_with_other_versions(self):
+        self.assertParserError(
+            ["--http10", "--expect-http-version", "1.1"],
+            "http10 conflicts with expect-http-version",
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,29 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class HttpVersionTest(CheckTestCase):
+    def setUp(self):
+        super(HttpVersionTest, self).setUp()
+        self.fake.announce("a")
+
+    def test_matching_version(self):
+        self.fake.protocol_version = "HTTP/1.1"
+        code, lines = self.run_check("--expect-http-version", "1.1")
+        self.assertEqual(code, 0)
+
+    def test_other_version_warns(self):
+        code, lines = self.run_check("--expect-http-version", "1.1")
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "expected HTTP/1.1, got HTTP/1.0")
+
+    def test_http2_rejected(self):
+        # requests can't negotiate HTTP/2, so it would never match.
+        self.assertParserError(
+            ["--expect-http-version", "2"], "invalid choice: '2'", code=2
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
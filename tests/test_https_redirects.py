@@ -0,0 +1,36 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class HttpsRedirectsTest(CheckTestCase):
+    def setUp(self):
+        super(HttpsRedirectsTest, self).setUp()
+        self.fake.announce("plain")
+        # Nothing listens there; the redirect mustn't be followed.
+        self.moved = self.fake.announce(
+            "moved",
+            statuses=[301],
+            headers={"Location": "https://127.0.0.1:1/moved/health"},
+        )
+
+    def test_redirects_counted_not_followed(self):
+        code, lines = self.run_check("--report-https-redirects")
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "1 of 2 http instances redirect to https")
+        self.assertLine(lines, "no redirect: %splain/health" % self.fake.url)
+        self.assertLine(lines, "(redirects to https)")
+        self.assertIn("https_redirects=1", lines[0])
+        self.assertEqual(self.moved.probes, 1)
+
+    def test_other_redirects_fail(self):
+        self.fake.announce(
+            "elsewhere", statuses=[302], headers={"Location": "/plain/health"}
+        )
+        code, lines = self.run_check("--report-https-redirects")
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "1 of 3 http instances redirect to https")
+
+
+if __name__ == "__main__":
+    unittest.main()
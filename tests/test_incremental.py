@@ -0,0 +1,38 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class IncrementalTest(CheckTestCase):
+    def run_incremental(self, *args):
+        return self.run_check("--incremental-state", self.path("incremental"), *args)
+
+    def test_first_run_probes_everything(self):
+        self.fake.announce("a")
+        self.fake.announce("b")
+        code, _ = self.run_incremental()
+        self.assertEqual(code, 0)
+        self.assertEqual(self.fake.probes(), {"a": 1, "b": 1})
+
+    def test_second_run_probes_only_the_delta(self):
+        self.fake.announce("ok")
+        self.fake.announce("failing", statuses=[500])
+        self.run_incremental()
+
+        self.fake.announce("new")
+        code, lines = self.run_incremental()
+        self.assertEqual(code, 2)
+        # The ok instance's verdict is reused; the failing one is re-checked
+        # and the new one checked for the first time.
+        self.assertEqual(self.fake.probes(), {"ok": 1, "failing": 2, "new": 1})
+        self.assertLine(lines, "cached from")
+
+    def test_expired_verdicts_are_probed_again(self):
+        self.fake.announce("a")
+        self.run_incremental("--incremental-ttl", "0.001")
+        self.run_incremental("--incremental-ttl", "0.001")
+        self.assertEqual(self.fake.probes(), {"a": 2})
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,40 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class InstanceTimeoutTest(CheckTestCase):
+    def test_generous_metadata_timeout_passes(self):
+        self.fake.announce("a", delay=1.5, metadata={"healthTimeoutMs": 3000})
+        code, lines = self.run_check("-t", "1")
+        self.assertEqual(code, 0)
+
+    def test_global_timeout_fails(self):
+        self.fake.announce("a", delay=1.5)
+        code, lines = self.run_check("-t", "1")
+        self.assertEqual(code, 2)
+
+    def test_only_that_instance(self):
+        self.fake.announce("a", delay=1.5, metadata={"healthTimeoutMs": 3000})
+        self.fake.announce("b", delay=1.5)
+        code, lines = self.run_check("-t", "1")
+        self.assertEqual(code, 2)
+        self.assertEqual(
+            [line for line in lines if line.startswith("check URI ")][-1],
+            "check URI %shealth" % self.fake.uri("a"),
+        )
+
+    def test_shorter_metadata_timeout(self):
+        self.fake.announce("a", delay=0.5, metadata={"healthTimeoutMs": "100"})
+        code, lines = self.run_check("-t", "5")
+        self.assertEqual(code, 2)
+
+    def test_invalid_metadata_ignored(self):
+        for name, value in (("a", 0), ("b", -1), ("c", "soon")):
+            self.fake.announce(name, delay=0.2, metadata={"healthTimeoutMs": value})
+        code, lines = self.run_check("-t", "5")
+        self.assertEqual(code, 0)
+
+
+if __name__ == "__main__":
+    unittest.main()
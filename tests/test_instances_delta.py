@@ -0,0 +1,33 @@
+import re
+import unittest
+
+from helpers import CheckTestCase
+
+
+class InstancesDeltaTest(CheckTestCase):
+    def run_delta(self):
+        code, lines = self.run_check("--state-cache", self.path("count.json"))
+        self.assertEqual(code, 0)
+        match = re.search(r"\binstances_delta=(-?\d+)\b", lines[0])
+        self.assertIsNotNone(match, lines[0])
+        return int(match.group(1))
+
+    def test_delta_across_runs(self):
+        self.fake.announce("a")
+        self.fake.announce("b")
+        self.assertEqual(self.run_delta(), 0)
+        self.fake.announce("c")
+        self.assertEqual(self.run_delta(), 1)
+        self.fake.withdraw("a")
+        self.fake.withdraw("b")
+        self.assertEqual(self.run_delta(), -2)
+        self.assertEqual(self.run_delta(), 0)
+
+    def test_no_delta_without_state_cache(self):
+        self.fake.announce("a")
+        code, lines = self.run_check()
+        self.assertNotIn("instances_delta", lines[0])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,60 @@
+import re
+import unittest
+
+from helpers import CheckTestCase, check
+
+PAIR = re.compile(r'(\w+)=("(?:[^"\\]|\\.)*"|\S*)(?: |$)')
+ESCAPE = re.compile(r"\\(.)")
+
+
+def unescape(match):
+    return "\n" if match.group(1) == "n" else match.group(1)
+
+
+def parse(line):
+    record = {}
+    pos = 0
+    while pos < len(line):
+        match = PAIR.match(line, pos)
+        if match is None:
+            raise ValueError("not logfmt at %s: %r" % (pos, line))
+        key, value = match.groups()
+        if value.startswith('"'):
+            value = ESCAPE.sub(unescape, value[1:-1])
+        record[key] = value
+        pos = match.end()
+    return record
+
+
+class LogfmtTest(CheckTestCase):
+    def test_records(self):
+        self.fake.announce("a")
+        self.fake.announce("b", statuses=[500])
+        code, lines = self.run_check("--output", "logfmt")
+        self.assertEqual(code, 2)
+        records = [parse(line) for line in lines]
+        health = dict((r["uri"], r) for r in records if r["topic"] == "health")
+        self.assertEqual(len(health), 2)
+        bad = health[self.fake.url + "b/health"]
+        self.assertEqual(bad["status"], "critical")
+        self.assertEqual(bad["code"], "2")
+        self.assertEqual(bad["service"], "svc")
+        self.assertEqual(bad["env"], "prod")
+        self.assertEqual(bad["endpoint"], "health")
+        self.assertEqual(bad["msg"], "500 from endpoint")
+        self.assertTrue(bad["ms"].isdigit(), bad)
+        summary = records[-1]
+        self.assertEqual(summary["topic"], "summary")
+        self.assertEqual(summary["code"], "2")
+        self.assertEqual((summary["critical"], summary["ok"]), ("1", "2"))
+
+    def test_quoting(self):
+        line = 'msg="500 from endpoint"'
+        self.assertEqual(check.logfmt([("msg", "500 from endpoint")]), line)
+        pairs = [("msg", 'say "hi"\nbye'), ("k", "v"), ("empty", "")]
+        self.assertEqual(parse(check.logfmt(pairs)), dict(pairs))
+        self.assertEqual(check.logfmt([("skipped", None), ("n", 3)]), "n=3")
+
+
+if __name__ == "__main__":
+    unittest.main()
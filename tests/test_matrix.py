@@ -0,0 +1,42 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class MatrixTest(CheckTestCase):
+    def setUp(self):
+        super(MatrixTest, self).setUp()
+        self.fake.announce("a")
+        self.fake.announce("b", routes={"ready": 503})
+        self.fake.announce("c", routes={"health": 404})
+
+    def matrix(self, lines):
+        # The grid follows the non-health results.
+        start = [i for i, line in enumerate(lines) if line.startswith("instance")]
+        self.assertEqual(len(start), 1, lines)
+        return [line.split() for line in lines[start[0] :]]
+
+    def test_grid(self):
+        code, lines = self.run_check("--matrix", "-e", "health", "-e", "ready")
+        # The overall status is the worst cell.
+        self.assertEqual(code, 2)
+        self.assertEqual(
+            self.matrix(lines),
+            [
+                ["instance", "health", "ready"],
+                [self.fake.uri("a"), "OK", "OK"],
+                [self.fake.uri("b"), "OK", "CRIT"],
+                [self.fake.uri("c"), "WARN", "OK"],
+            ],
+        )
+        self.assertFalse(any(line.startswith("health ") for line in lines))
+
+    def test_single_endpoint(self):
+        code, lines = self.run_check("--matrix")
+        self.assertEqual(code, 1)
+        self.assertEqual(len(self.matrix(lines)), 4)
+        self.assertEqual(self.matrix(lines)[0], ["instance", "health"])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,38 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class MaxFailuresShownTest(CheckTestCase):
+    def setUp(self):
+        super(MaxFailuresShownTest, self).setUp()
+        for i in range(5):
+            self.fake.announce("f%s" % i, statuses=(500,))
+        self.fake.announce("g", statuses=(404,))
+        self.fake.announce("ok")
+
+    def test_truncation_note(self):
+        code, lines = self.run_check("--max-failures-shown", "2")
+        self.assertEqual(code, 2)
+        failures = [line for line in lines if line.startswith("health critical")]
+        self.assertEqual(len(failures), 2)
+        self.assertIn("...and 4 more failures", lines)
+        self.assertFalse(any(line.startswith("health warning") for line in lines))
+        # Ok results are still shown, and counts reflect everything.
+        self.assertIn("health ok: 200 from endpoint", lines)
+        self.assertIn(" ok=1 warn=1 crit=5 unknown=0", lines[0])
+
+    def test_within_limit(self):
+        code, lines = self.run_check("--max-failures-shown", "6")
+        self.assertEqual(code, 2)
+        self.assertFalse(any(line.startswith("...and") for line in lines))
+        self.assertEqual(sum(line.startswith("health ") for line in lines), 7)
+
+    def test_must_be_positive(self):
+        self.assertParserError(
+            ["--max-failures-shown", "0"], "max-failures-shown must be positive"
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,44 @@
+import re
+import unittest
+
+from helpers import CheckTestCase
+
+
+class MaxLatencyTest(CheckTestCase):
+    def perf(self, lines):
+        return lines[0].split(" | ", 1)[1].split()
+
+    def test_matches_slowest_probe(self):
+        for name, delay in (("a", 0), ("b", 0.2), ("c", 0.4)):
+            self.fake.announce(name, delay=delay)
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+        perf = self.perf(lines)
+        durations = dict(
+            re.match(r".*_([abc])=(\d+)ms$", p).groups()
+            for p in perf
+            if p.endswith("ms")
+        )
+        self.assertEqual(max(durations, key=lambda k: int(durations[k])), "c")
+        self.assertIn("max_latency_ms=%s" % durations["c"], perf)
+        self.assertGreaterEqual(int(durations["c"]), 400)
+
+    def test_thresholds(self):
+        self.fake.announce("a")
+        code, lines = self.run_check("--latency-warn", "500", "--latency-crit", "900")
+        self.assertTrue(
+            any(re.match(r"^max_latency_ms=\d+;500;900$", p) for p in self.perf(lines))
+        )
+
+    def test_none_without_health_checks(self):
+        self.fake.announce("a")
+        code, lines = self.run_check("--no-healthcheck")
+        self.assertNotIn("max_latency_ms", lines[0])
+
+    def test_none_without_instances(self):
+        code, lines = self.run_check()
+        self.assertNotIn("max_latency_ms", lines[0])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,37 @@
+import re
+import unittest
+
+from helpers import CheckTestCase
+
+
+class MaxOutputBytesTest(CheckTestCase):
+    def setUp(self):
+        super(MaxOutputBytesTest, self).setUp()
+        for i in range(10):
+            self.fake.announce("ok%s" % i)
+        self.fake.announce("bad", statuses=[500])
+
+    def test_truncation_keeps_summary(self):
+        _, full = self.run_check()
+        code, lines = self.run_check("--max-output-bytes", "600")
+        self.assertEqual(code, 2)
+        self.assertLessEqual(len("\n".join(lines).encode("utf-8")) + 1, 600)
+        # The worst result leads, with the perf data.
+        self.assertTrue(lines[0].startswith("health critical: 500"), lines[0])
+        self.assertIn(" | ", lines[0])
+        match = re.match(r"\.\.\.\(truncated (\d+) lines\)$", lines[-1])
+        self.assertTrue(match, lines[-1])
+        self.assertEqual(int(match.group(1)), len(full) - (len(lines) - 1))
+
+    def test_short_output_untouched(self):
+        code, lines = self.run_check("--max-output-bytes", "100000")
+        self.assertNotIn("truncated", lines[-1])
+
+    def test_oversized_summary_kept(self):
+        code, lines = self.run_check("--max-output-bytes", "10")
+        self.assertTrue(lines[0].startswith("health critical: 500"), lines[0])
+        self.assertEqual(len(lines), 2)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,65 @@
+import unittest
+
+from helpers import check
+
+
+def ann(metadata):
+    return {"serviceType": "svc", "metadata": metadata}
+
+
+class MetaStringTest(unittest.TestCase):
+    def test_missing_or_null_metadata(self):
+        self.assertIsNone(check.meta_string({}, "k"))
+        self.assertIsNone(check.meta_string(ann(None), "k"))
+        self.assertIsNone(check.meta_string(ann({}), "k"))
+        self.assertIsNone(check.meta_string(ann({"k": None}), "k"))
+
+    def test_strings(self):
+        self.assertEqual(check.meta_string(ann({"k": "host1"}), "k"), "host1")
+        self.assertEqual(check.meta_string(ann({"k": ""}), "k"), "")
+
+    def test_numbers(self):
+        self.assertEqual(check.meta_string(ann({"k": 42}), "k"), "42")
+        self.assertEqual(check.meta_string(ann({"k": 1.5}), "k"), "1.5")
+
+    def test_wrong_types(self):
+        for value in (True, False, [1], {"a": 1}):
+            self.assertIsNone(check.meta_string(ann({"k": value}), "k"), value)
+        self.assertIsNone(check.meta_string(ann(["k"]), "k"))
+
+
+class MetaFloatTest(unittest.TestCase):
+    def test_missing_or_null_metadata(self):
+        self.assertIsNone(check.meta_float({}, "k"))
+        self.assertIsNone(check.meta_float(ann(None), "k"))
+        self.assertIsNone(check.meta_float(ann({"k": None}), "k"))
+
+    def test_numbers(self):
+        self.assertEqual(check.meta_float(ann({"k": 3}), "k"), 3.0)
+        self.assertEqual(check.meta_float(ann({"k": 2.5}), "k"), 2.5)
+
+    def test_numeric_strings(self):
+        self.assertEqual(check.meta_float(ann({"k": "250"}), "k"), 250.0)
+        self.assertEqual(check.meta_float(ann({"k": " 1e3 "}), "k"), 1000.0)
+        self.assertIsNone(check.meta_float(ann({"k": "soon"}), "k"))
+
+    def test_wrong_types(self):
+        for value in (True, False, [1], {"a": 1}):
+            self.assertIsNone(check.meta_float(ann({"k": value}), "k"), value)
+
+
+class ServerTokenTest(unittest.TestCase):
+    def test_counted_via_meta_string(self):
+        anns = [
+            ann({"server-token": "t1"}),
+            ann({"server-token": "t1"}),
+            ann({"server-token": 7}),
+            ann(None),
+            ann({"server-token": True}),
+        ]
+        # Those without a usable token each count once.
+        self.assertEqual(check.Main.count_announcements(anns), 4)
+
+
+if __name__ == "__main__":
+    unittest.main()
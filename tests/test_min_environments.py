@@ -0,0 +1,38 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class MinEnvironmentsTest(CheckTestCase):
+    def run_envs(self):
+        return self.run_check(
+            "--min-environments-crit", "2", "--min-environments-warn", "3"
+        )
+
+    def test_all_in_one_environment(self):
+        for name in "abcd":
+            self.fake.announce(name)
+        code, lines = self.run_envs()
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "environments critical: 1 distinct")
+        self.assertIn("prod", lines)
+        self.assertIn(" environments=1 ", lines[0])
+
+    def test_below_warning(self):
+        self.fake.announce("a", environment="us-east")
+        self.fake.announce("b", environment="us-west")
+        self.fake.announce("c", environment="")
+        code, lines = self.run_envs()
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "environments warning: 2 distinct")
+
+    def test_enough_environments(self):
+        for env in ("us-east", "us-west", "eu-west"):
+            self.fake.announce(env, environment=env)
+        code, lines = self.run_envs()
+        self.assertEqual(code, 0)
+        self.assertIn("eu-west,us-east,us-west", lines)
+
+
+if __name__ == "__main__":
+    unittest.main()
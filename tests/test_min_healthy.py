@@ -0,0 +1,51 @@
+import unittest
+
+from helpers import CheckTestCase
+
+QUOTA = ["-c", "3", "-w", "4"]
+
+
+class MinHealthyTest(CheckTestCase):
+    def setUp(self):
+        super(MinHealthyTest, self).setUp()
+        for name in "ab":
+            self.fake.announce(name)
+        # Announced, so they count for the quota, but not healthy.
+        for name in "cde":
+            self.fake.announce(name, statuses=(404,))
+
+    def test_below_floor_while_quota_met(self):
+        code, lines = self.run_check(
+            *QUOTA + ["--min-healthy-crit", "3", "--min-healthy-warn", "4"]
+        )
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "announcements ok: 5")
+        self.assertLine(lines, "healthy critical: 2 of 5 instances healthy")
+        self.assertLine(lines, "crit./warn thresh.: 3/4")
+        self.assertIn(" healthy=2 ", lines[0])
+
+    def test_warning_floor(self):
+        code, lines = self.run_check(
+            *QUOTA + ["--min-healthy-crit", "1", "--min-healthy-warn", "3"]
+        )
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "healthy warning: 2 of 5 instances healthy")
+
+    def test_floor_met(self):
+        code, lines = self.run_check(*QUOTA + ["--min-healthy-warn", "2"])
+        self.assertLine(lines, "healthy ok: 2 of 5 instances healthy")
+
+    def test_off_by_default(self):
+        code, lines = self.run_check(*QUOTA)
+        self.assertFalse(any(line.startswith("healthy ") for line in lines))
+        self.assertNotIn(" healthy=", lines[0])
+
+    def test_warn_below_crit_rejected(self):
+        self.assertParserError(
+            ["--min-healthy-crit", "3", "--min-healthy-warn", "2"],
+            "min-healthy-warn must be at least as large as min-healthy-crit",
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
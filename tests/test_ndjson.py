@@ -0,0 +1,36 @@
+import json
+import unittest
+
+from helpers import CheckTestCase
+
+
+class NdjsonTest(CheckTestCase):
+    def run_ndjson(self, *args):
+        code, lines = self.run_check("--output", "ndjson", *args)
+        return code, [json.loads(line) for line in lines]
+
+    def test_records_and_summary(self):
+        self.fake.announce("a")
+        self.fake.announce("b", statuses=[500])
+        code, records = self.run_ndjson()
+        self.assertEqual(code, 2)
+        health = [r for r in records if r["topic"] == "health"]
+        self.assertEqual(sorted(r["code"] for r in health), [0, 2])
+        self.assertFalse(any("corrected" in r for r in records))
+        self.assertEqual(records[-1]["topic"], "summary")
+        self.assertEqual(records[-1]["critical"], 1)
+
+    def test_policy_changes_are_corrected(self):
+        self.fake.announce("a", statuses=[500])
+        code, records = self.run_ndjson("--warn-only")
+        self.assertEqual(code, 1)
+        health = [r for r in records if r["topic"] == "health"]
+        # Streamed as critical, then corrected to the final warning.
+        self.assertEqual(
+            [(r["code"], r.get("corrected")) for r in health], [(2, None), (1, True)]
+        )
+        self.assertEqual(records[-1]["code"], 1)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,65 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class NumericAssertTest(CheckTestCase):
+    def test_below_greater_than_threshold(self):
+        self.fake.announce("a", bodies=({"freeDiskPercent": 12},))
+        code, lines = self.run_check("--numeric-assert", "freeDiskPercent:>:20")
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "freeDiskPercent 12, expected > 20")
+
+    def test_above_threshold_ok(self):
+        self.fake.announce("a", bodies=({"freeDiskPercent": 42.5},))
+        code, lines = self.run_check("--numeric-assert", "freeDiskPercent:>:20")
+        self.assertEqual(code, 0)
+
+    def test_severities(self):
+        self.fake.announce("a", bodies=({"freeDiskPercent": 12},))
+        args = [
+            "--numeric-assert",
+            "freeDiskPercent:>:20:warning",
+            "--numeric-assert",
+            "freeDiskPercent:>:10:critical",
+        ]
+        code, lines = self.run_check(*args)
+        self.assertEqual(code, 1)
+        self.fake.instances["a"].bodies = [{"freeDiskPercent": 5}]
+        code, lines = self.run_check(*args)
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "freeDiskPercent 5, expected > 20")
+        self.assertLine(lines, "freeDiskPercent 5, expected > 10")
+
+    def test_dotted_field(self):
+        self.fake.announce("a", bodies=({"disk": {"free": 12}},))
+        code, lines = self.run_check("--numeric-assert", "disk.free:>=:12")
+        self.assertEqual(code, 0)
+        code, lines = self.run_check("--numeric-assert", "disk.free:<:12")
+        self.assertEqual(code, 1)
+
+    def test_missing_or_not_a_number(self):
+        self.fake.announce("a", bodies=({"freeDiskPercent": "12"},))
+        self.fake.announce("b", bodies=(b"not json",))
+        code, lines = self.run_check("--numeric-assert", "freeDiskPercent:>:20")
+        self.assertEqual(code, 1)
+        self.assertEqual(
+            sum("freeDiskPercent is not a number" in line for line in lines), 2
+        )
+
+    def test_failing_status_not_asserted(self):
+        self.fake.announce("a", statuses=(500,), bodies=({"freeDiskPercent": 12},))
+        code, lines = self.run_check("--numeric-assert", "freeDiskPercent:>:20")
+        self.assertEqual(code, 2)
+        self.assertFalse(any("expected > 20" in line for line in lines))
+
+    def test_bad_assert(self):
+        self.assertParserError(
+            ["--numeric-assert", "freeDiskPercent:~:20"],
+            "invalid numeric assert, want FIELD:OP:VALUE[:SEVERITY]",
+            code=2,
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
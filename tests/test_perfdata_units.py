@@ -0,0 +1,42 @@
+import re
+import unittest
+
+from helpers import CheckTestCase
+
+
+def perf(lines, label):
+    match = re.search(r"(?:^| )%s=([^ ;]*)" % label, lines[0].split(" | ", 1)[1])
+    return match and match.group(1)
+
+
+class PerfdataUnitsTest(CheckTestCase):
+    def setUp(self):
+        super(PerfdataUnitsTest, self).setUp()
+        self.fake.announce("a")
+
+    def test_no_units_by_default(self):
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+        self.assertRegex(perf(lines, "discovery_duration"), r"^\d+\.\d{3}$")
+        self.assertEqual(perf(lines, "instances"), "1")
+
+    def test_annotated(self):
+        code, lines = self.run_check("--annotate-perfdata-with-units")
+        self.assertEqual(code, 0)
+        self.assertRegex(perf(lines, "discovery_duration"), r"^\d+\.\d{3}s$")
+        self.assertEqual(perf(lines, "instances"), "1")
+
+    def test_instances_unit(self):
+        code, lines = self.run_check("--perfdata-units", "c")
+        self.assertEqual(code, 0)
+        self.assertEqual(perf(lines, "instances"), "1c")
+        # Implies annotating the rest too.
+        self.assertRegex(perf(lines, "discovery_duration"), r"s$")
+
+    def test_probe_durations_always_ms(self):
+        code, lines = self.run_check()
+        self.assertTrue(re.search(r"=\d+ms\b", lines[0].split(" | ", 1)[1]))
+
+
+if __name__ == "__main__":
+    unittest.main()
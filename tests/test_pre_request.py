@@ -0,0 +1,78 @@
+import unittest
+
+from helpers import CheckTestCase
+
+TOKEN = "t0k3n"
+
+
+def health(request):
+    # Like the legacy admin endpoint: no token, no health.
+    if request.headers.get("X-CSRF-Token") != TOKEN:
+        return 403, {"error": "bad token"}
+    return 200, {"status": "UP"}
+
+
+class PreRequestTest(CheckTestCase):
+    def test_token_from_json_field(self):
+        routes = {"csrf": lambda request: (200, {"token": TOKEN}), "health": health}
+        a = self.fake.announce("a", routes=routes)
+        code, lines = self.run_check(
+            "--pre-request", "csrf", "--pre-request-field", "token"
+        )
+        self.assertEqual(code, 0)
+        self.assertEqual([r.path for r in a.requests], ["/a/csrf", "/a/health"])
+
+    def test_token_from_header(self):
+        routes = {"csrf": 204, "health": health}
+        self.fake.announce("a", routes=routes, headers={"X-Token": TOKEN})
+        code, lines = self.run_check(
+            "--pre-request", "csrf", "--pre-request-header", "X-Token"
+        )
+        self.assertEqual(code, 0)
+
+    def test_custom_token_header(self):
+        routes = {
+            "csrf": lambda request: (200, {"token": TOKEN}),
+            "health": lambda request: (
+                200 if request.headers.get("X-Admin-Token") == TOKEN else 403,
+                None,
+            ),
+        }
+        self.fake.announce("a", routes=routes)
+        code, lines = self.run_check(
+            "--pre-request",
+            "csrf",
+            "--pre-request-field",
+            "token",
+            "--token-header",
+            "X-Admin-Token",
+        )
+        self.assertEqual(code, 0)
+
+    def test_wrong_token_rejected(self):
+        routes = {"csrf": lambda request: (200, {"token": "stale"}), "health": health}
+        self.fake.announce("a", routes=routes)
+        code, lines = self.run_check(
+            "--pre-request", "csrf", "--pre-request-field", "token"
+        )
+        self.assertNotEqual(code, 0)
+        self.assertLine(lines, "403")
+
+    def test_no_token(self):
+        routes = {"csrf": lambda request: (200, {}), "health": health}
+        a = self.fake.announce("a", routes=routes)
+        code, lines = self.run_check(
+            "--pre-request", "csrf", "--pre-request-field", "token"
+        )
+        self.assertNotEqual(code, 0)
+        self.assertLine(lines, "no token from %scsrf (200)" % self.fake.uri("a"))
+        self.assertEqual([r.path for r in a.requests], ["/a/csrf"])
+
+    def test_without_pre_request(self):
+        self.fake.announce("a", routes={"health": health})
+        code, lines = self.run_check()
+        self.assertNotEqual(code, 0)
+
+
+if __name__ == "__main__":
+    unittest.main()
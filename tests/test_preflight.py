@@ -0,0 +1,36 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class PreflightTest(CheckTestCase):
+    def test_happy_path(self):
+        self.fake.announce("a", statuses=[500])
+        self.fake.announce("b")
+        code, lines = self.run_check("--preflight")
+        self.assertEqual(code, 0)
+        self.assertEqual(lines[0], "preflight ok")
+        self.assertIn("svc: 2 announcements", lines)
+        self.assertLine(lines, "discovery %s in " % self.fake.url)
+        self.assertIn("endpoints: health", lines)
+        # No health checks, so the failing instance doesn't matter.
+        self.assertEqual(self.fake.probes(), {"a": 0, "b": 0})
+
+    def test_bad_discovery_url(self):
+        code, lines = self.run_check("--preflight", "-d", "http://127.0.0.1:1/")
+        self.assertEqual(code, 3)
+        self.assertEqual(lines[0], "preflight failed: failed to get announcements")
+
+    def test_no_announcements(self):
+        code, lines = self.run_check("--preflight", "-s", "other")
+        self.assertEqual(code, 3)
+        self.assertEqual(lines[0], "preflight failed: no announcements for svc,other")
+
+    def test_bad_flags(self):
+        self.assertParserError(
+            ["--preflight", "--total-timeout", "0"], "total-timeout must be positive"
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
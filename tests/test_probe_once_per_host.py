@@ -0,0 +1,43 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class ProbeOncePerHostTest(CheckTestCase):
+    def localhost(self, name):
+        return self.fake.uri(name).replace("127.0.0.1", "localhost")
+
+    def test_one_probe_per_uri_host(self):
+        self.fake.announce("a")
+        self.fake.announce("b", statuses=[500])
+        self.fake.announce("c", serviceUri=self.localhost("c"))
+        code, lines = self.run_check("--probe-once-per-host")
+        self.assertEqual(code, 0)
+        self.assertEqual(self.fake.probes(), {"a": 1, "b": 0, "c": 1})
+        # b shares a's host, and so its result.
+        self.assertLine(lines, "check URI %sb/health" % self.fake.url)
+        self.assertLine(lines, "same host as %sa/health" % self.fake.url)
+
+    def test_one_probe_per_server_token(self):
+        self.fake.announce("a", metadata={"server-token": "t1"})
+        self.fake.announce("b", metadata={"server-token": "t1"})
+        self.fake.announce("c", metadata={"server-token": "t2"})
+        self.fake.announce("d")
+        self.fake.announce("e")
+        code, lines = self.run_check("--probe-once-per-host", "server-token")
+        self.assertEqual(code, 0)
+        # Without a token, an instance is its own group.
+        self.assertEqual(self.fake.probes(), {"a": 1, "b": 0, "c": 1, "d": 1, "e": 1})
+
+    def test_failure_applies_to_group(self):
+        self.fake.announce("a", statuses=[500])
+        self.fake.announce("b")
+        code, lines = self.run_check("--probe-once-per-host")
+        self.assertEqual(code, 2)
+        self.assertEqual(self.fake.probes(), {"a": 1, "b": 0})
+        health = [line for line in lines if line.startswith("health critical")]
+        self.assertEqual(len(health), 2, lines)
+
+
+if __name__ == "__main__":
+    unittest.main()
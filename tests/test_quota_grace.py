@@ -0,0 +1,47 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class QuotaGraceTest(CheckTestCase):
+    def setUp(self):
+        super(QuotaGraceTest, self).setUp()
+        for name in "ab":
+            self.fake.announce(name)
+
+    def run_quota(self):
+        return self.run_check(
+            "-c",
+            "5",
+            "-w",
+            "6",
+            "--quota-grace-runs",
+            "3",
+            "--quota-state",
+            self.path("quota"),
+        )
+
+    def test_shortfall_warns_until_grace_runs_out(self):
+        for run in (1, 2):
+            code, lines = self.run_quota()
+            self.assertEqual(code, 1)
+            self.assertLine(lines, "shortfall run %s of 3 grace runs" % run)
+        code, lines = self.run_quota()
+        self.assertEqual(code, 2)
+        self.assertNotIn("grace runs", "\n".join(lines))
+
+    def test_recovery_starts_over(self):
+        self.run_quota()
+        self.run_quota()
+        for name in "cdef":
+            self.fake.announce(name)
+        self.assertEqual(self.run_quota()[0], 0)
+        for name in "cdef":
+            self.fake.withdraw(name)
+        code, lines = self.run_quota()
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "shortfall run 1 of 3 grace runs")
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,38 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class RemovalGraceTest(CheckTestCase):
+    def setUp(self):
+        super(RemovalGraceTest, self).setUp()
+        self.fake.announce("a")
+        self.fake.announce("b")
+
+    def run_grace(self, grace):
+        return self.run_check(
+            "-w",
+            "2",
+            "--removal-grace",
+            grace,
+            "--removal-state",
+            self.path("removal"),
+        )
+
+    def test_dropped_instance_counts_within_grace(self):
+        self.assertEqual(self.run_grace("60")[0], 0)
+        self.fake.withdraw("b")
+        code, lines = self.run_grace("60")
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "1 recently removed announcements counted")
+
+    def test_dropped_instance_is_missed_after_grace(self):
+        self.run_grace("60")
+        self.fake.withdraw("b")
+        code, lines = self.run_grace("0.001")
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "announcements warning: 1")
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,64 @@
+import os
+import re
+import unittest
+
+from helpers import TLS, CheckTestCase, check
+
+
+class ReportTlsTest(CheckTestCase):
+    def check_details(self):
+        self.fake.serve_tls()
+        self.fake.announce("a", serviceUri=self.fake.tls_uri("a"))
+        code, lines = self.run_check(
+            "--report-tls", "--ca-file", os.path.join(TLS, "ca.pem")
+        )
+        self.assertEqual(code, 0)
+        tls = [line for line in lines if line.startswith("tls ")]
+        self.assertEqual(len(tls), 1, lines)
+        self.assertRegex(
+            tls[0],
+            r"^tls TLSv1\.[23] [\w-]+ \((128|256) bits\) subject commonName=localhost$",
+        )
+        self.assertFalse(any(line.startswith("weak TLS") for line in lines))
+
+    def test_captured_details(self):
+        self.check_details()
+
+    def test_captured_details_keep_alive(self):
+        # The connection stays open, rather than the response owning it.
+        self.fake.protocol_version = "HTTP/1.1"
+        self.check_details()
+
+    def test_plain_http(self):
+        self.fake.announce("a")
+        code, lines = self.run_check("--report-tls")
+        self.assertEqual(code, 0)
+        self.assertFalse(any(line.startswith("tls ") for line in lines))
+
+    def test_off_by_default(self):
+        self.fake.serve_tls()
+        self.fake.announce("a", serviceUri=self.fake.tls_uri("a"))
+        code, lines = self.run_check("--ca-file", os.path.join(TLS, "ca.pem"))
+        self.assertEqual(code, 0)
+        self.assertFalse(any(line.startswith("tls ") for line in lines))
+
+    def test_weaknesses(self):
+        def tls(version="TLSv1.3", cipher="TLS_AES_256_GCM_SHA384", bits=256):
+            return {"version": version, "cipher": cipher, "bits": bits}
+
+        self.assertEqual(check.tls_weaknesses(tls()), [])
+        self.assertEqual(check.tls_weaknesses(tls(version="TLSv1")), ["TLSv1"])
+        self.assertEqual(
+            check.tls_weaknesses(tls(cipher="RC4-SHA", bits=128)), ["RC4-SHA"]
+        )
+        self.assertEqual(
+            check.tls_weaknesses(tls(cipher="SOMETHING", bits=56)), ["56-bit cipher"]
+        )
+        self.assertEqual(
+            check.tls_weaknesses(tls(version="SSLv3", cipher="DES-CBC-SHA", bits=56)),
+            ["SSLv3", "DES-CBC-SHA"],
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,26 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class RequireAllHealthyTest(CheckTestCase):
+    def setUp(self):
+        super(RequireAllHealthyTest, self).setUp()
+        for name in ("a", "b", "c", "d"):
+            self.fake.announce(name)
+        self.fake.announce("e", statuses=[404])
+
+    def test_one_warning_is_tolerated_by_default(self):
+        code, _ = self.run_check()
+        self.assertEqual(code, 1)
+
+    def test_one_warning_is_critical(self):
+        code, lines = self.run_check("--require-all-healthy")
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "1 of 5 instances not ok; all required healthy")
+        # The instance's own result is still there.
+        self.assertLine(lines, "health warning: 404 from endpoint")
+
+
+if __name__ == "__main__":
+    unittest.main()
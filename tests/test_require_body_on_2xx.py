@@ -0,0 +1,31 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class RequireBodyOn2xxTest(CheckTestCase):
+    def test_empty_200_warns(self):
+        self.fake.announce("a", bodies=(b"",))
+        code, lines = self.run_check("--require-body-on-2xx")
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "empty body")
+
+    def test_whitespace_200_warns(self):
+        self.fake.announce("a", bodies=(b" \r\n\t",))
+        code, lines = self.run_check("--require-body-on-2xx")
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "empty body")
+
+    def test_200_with_body_ok(self):
+        self.fake.announce("a", bodies=({"status": "UP"},))
+        code, lines = self.run_check("--require-body-on-2xx")
+        self.assertEqual(code, 0)
+
+    def test_empty_200_ok_without_flag(self):
+        self.fake.announce("a", bodies=(b"",))
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+
+
+if __name__ == "__main__":
+    unittest.main()
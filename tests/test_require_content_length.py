@@ -0,0 +1,25 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class RequireContentLengthTest(CheckTestCase):
+    def test_chunked_warns(self):
+        self.fake.announce("a", chunks=[(0, b'{"status":'), (0, b' "UP"}')])
+        code, lines = self.run_check("--require-content-length")
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "no Content-Length (Transfer-Encoding chunked)")
+
+    def test_fixed_length_ok(self):
+        self.fake.announce("a")
+        code, lines = self.run_check("--require-content-length")
+        self.assertEqual(code, 0)
+
+    def test_chunked_ok_without_flag(self):
+        self.fake.announce("a", chunks=[(0, b'{"status": "UP"}')])
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+
+
+if __name__ == "__main__":
+    unittest.main()
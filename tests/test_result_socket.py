@@ -0,0 +1,75 @@
+import io
+import json
+import os
+import socket
+import threading
+import unittest
+from contextlib import redirect_stderr
+
+from helpers import CheckTestCase
+
+
+class ResultSocketTest(CheckTestCase):
+    def setUp(self):
+        super(ResultSocketTest, self).setUp()
+        self.fake.announce("a")
+        self.fake.announce("b", statuses=(500,))
+        self.socket_path = self.path("result.sock")
+
+    def listen(self, kind):
+        sock = socket.socket(socket.AF_UNIX, kind)
+        self.addCleanup(sock.close)
+        sock.bind(self.socket_path)
+        sock.settimeout(5)
+        return sock
+
+    def check_payload(self, payload):
+        doc = json.loads(payload.decode("utf-8"))
+        self.assertEqual(doc["status"], "critical")
+        self.assertEqual(doc["code"], 2)
+        # The announcements result is ok too.
+        self.assertEqual((doc["critical"], doc["ok"]), (1, 2))
+        self.assertEqual(
+            sorted(r["status"] for r in doc["results"] if "uri" in r),
+            ["critical", "ok"],
+        )
+
+    def test_datagram_socket(self):
+        sock = self.listen(socket.SOCK_DGRAM)
+        code, lines = self.run_check("--result-socket", self.socket_path)
+        self.assertEqual(code, 2)
+        # Still written to stdout as usual.
+        self.assertLine(lines, "health critical: 500 from endpoint")
+        self.check_payload(sock.recv(1 << 20))
+
+    def test_stream_socket(self):
+        sock = self.listen(socket.SOCK_STREAM)
+        sock.listen(1)
+        received = []
+
+        def accept():
+            conn, _ = sock.accept()
+            with conn:
+                chunks = iter(lambda: conn.recv(65536), b"")
+                received.append(b"".join(chunks))
+
+        thread = threading.Thread(target=accept)
+        thread.start()
+        code, lines = self.run_check("--result-socket", self.socket_path)
+        thread.join(5)
+        self.assertEqual(code, 2)
+        self.check_payload(received[0])
+
+    def test_missing_socket(self):
+        err = io.StringIO()
+        with redirect_stderr(err):
+            code, lines = self.run_check("--result-socket", self.socket_path)
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "health critical: 500 from endpoint")
+        msg = "failed to write result socket %s" % self.socket_path
+        self.assertIn(msg, err.getvalue())
+        self.assertFalse(os.path.exists(self.socket_path))
+
+
+if __name__ == "__main__":
+    unittest.main()
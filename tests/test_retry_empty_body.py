@@ -0,0 +1,47 @@
+import time
+import unittest
+
+from helpers import CheckTestCase
+
+
+class RetryEmptyBodyTest(CheckTestCase):
+    def test_empty_then_full(self):
+        a = self.fake.announce("a", bodies=(b"", b'{"status": "UP"}'))
+        started = time.time()
+        code, lines = self.run_check(
+            "--require-body-on-2xx", "--retry-empty-body", "0.3"
+        )
+        self.assertEqual(code, 0)
+        self.assertEqual(a.probes, 2)
+        self.assertGreaterEqual(time.time() - started, 0.3)
+
+    def test_retried_only_once(self):
+        a = self.fake.announce("a", bodies=(b"",))
+        code, lines = self.run_check(
+            "--require-body-on-2xx", "--retry-empty-body", "0.1"
+        )
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "empty body")
+        self.assertEqual(a.probes, 2)
+
+    def test_not_retried_without_flag(self):
+        a = self.fake.announce("a", bodies=(b"", b'{"status": "UP"}'))
+        code, lines = self.run_check("--require-body-on-2xx")
+        self.assertEqual(code, 1)
+        self.assertEqual(a.probes, 1)
+
+    def test_non_2xx_not_retried(self):
+        a = self.fake.announce("a", statuses=(503, 200), bodies=(b"", b"UP"))
+        code, lines = self.run_check("--retry-empty-body", "0.1")
+        self.assertEqual(code, 2)
+        self.assertEqual(a.probes, 1)
+
+    def test_full_body_not_retried(self):
+        a = self.fake.announce("a")
+        code, lines = self.run_check("--retry-empty-body")
+        self.assertEqual(code, 0)
+        self.assertEqual(a.probes, 1)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,34 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class RetryOnStatusTest(CheckTestCase):
+    def run_retry(self, *args):
+        return self.run_check("--retry-on-status", "502,503,504", *args)
+
+    def test_transient_status_retried(self):
+        self.fake.announce("a", statuses=[502, 200])
+        code, lines = self.run_retry()
+        self.assertEqual(code, 0)
+        self.assertEqual(self.fake.probes(), {"a": 2})
+        self.assertLine(lines, "2 attempts")
+
+    def test_other_status_not_retried(self):
+        self.fake.announce("a", statuses=[500, 200])
+        code, lines = self.run_retry()
+        self.assertEqual(code, 2)
+        self.assertEqual(self.fake.probes(), {"a": 1})
+        self.assertLine(lines, "health critical: 500")
+
+    def test_last_status_kept_when_retries_run_out(self):
+        self.fake.announce("a", statuses=[503, 503, 504, 200])
+        code, lines = self.run_retry("--retries", "2")
+        self.assertEqual(code, 2)
+        self.assertEqual(self.fake.probes(), {"a": 3})
+        self.assertLine(lines, "health critical: 504")
+        self.assertLine(lines, "3 attempts")
+
+
+if __name__ == "__main__":
+    unittest.main()
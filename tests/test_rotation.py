@@ -0,0 +1,46 @@
+import unittest
+
+from helpers import CheckTestCase
+
+ROTATION = ["--rotation-field", "inRotation"]
+
+
+class RotationTest(CheckTestCase):
+    def test_mixed(self):
+        self.fake.announce("a", bodies=({"status": "UP", "inRotation": True},))
+        self.fake.announce("b", bodies=({"status": "UP", "inRotation": False},))
+        self.fake.announce("c", bodies=({"status": "UP"},))
+        self.fake.announce("d", statuses=(500,), bodies=({"inRotation": True},))
+        code, lines = self.run_check(*ROTATION)
+        self.assertEqual(code, 2)
+        # Healthy a and c, which says nothing, count as serving.
+        self.assertIn(" in_rotation=2 ", lines[0])
+        self.assertLine(lines, "rotation ok: 1 healthy instances out of rotation")
+        self.assertIn(self.fake.uri("b") + "health", lines)
+        self.assertIn("(out of rotation)", lines)
+
+    def test_all_parked_warns(self):
+        for name in "ab":
+            self.fake.announce(name, bodies=({"inRotation": False},))
+        code, lines = self.run_check(*ROTATION)
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "rotation warning: 2 healthy instances out of rotation")
+        self.assertIn(" in_rotation=0 ", lines[0])
+
+    def test_all_serving(self):
+        for name in "ab":
+            self.fake.announce(name, bodies=({"inRotation": True},))
+        code, lines = self.run_check(*ROTATION)
+        self.assertEqual(code, 0)
+        self.assertIn(" in_rotation=2 ", lines[0])
+        self.assertFalse(any(line.startswith("rotation ") for line in lines))
+
+    def test_non_boolean_ignored(self):
+        self.fake.announce("a", bodies=({"inRotation": "false"},))
+        code, lines = self.run_check(*ROTATION)
+        self.assertEqual(code, 0)
+        self.assertIn(" in_rotation=1 ", lines[0])
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,52 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class SampleAboveTest(CheckTestCase):
+    def announce(self, count):
+        for i in range(count):
+            self.fake.announce("i%02d" % i)
+
+    def probed(self):
+        return sum(1 for n in self.fake.probes().values() if n)
+
+    def test_crossing_the_threshold(self):
+        self.announce(4)
+        code, lines = self.run_check("--sample-above", "4", "--sample-size", "2")
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "sampling ok: probed all 4 instances, 4 or fewer")
+        self.assertEqual(self.probed(), 4)
+
+        # Up past the threshold: a sample.
+        self.fake.announce("i04")
+        code, lines = self.run_check("--sample-above", "4", "--sample-size", "2")
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "sampling ok: probed 2 of 5 instances, above 4")
+        self.assertEqual(sum(self.fake.probes().values()), 4 + 2)
+
+        # And back down: all of them again.
+        self.fake.withdraw("i04")
+        for instance in self.fake.instances.values():
+            instance.requests = []
+        code, lines = self.run_check("--sample-above", "4", "--sample-size", "2")
+        self.assertLine(lines, "sampling ok: probed all 4 instances, 4 or fewer")
+        self.assertEqual(self.probed(), 4)
+
+    def test_sample_size_defaults_to_threshold(self):
+        self.announce(6)
+        code, lines = self.run_check("--sample-above", "3")
+        self.assertLine(lines, "sampling ok: probed 3 of 6 instances, above 3")
+        self.assertEqual(self.probed(), 3)
+        # Quota counts all announced instances.
+        self.assertIn(" instances=6 ", lines[0])
+
+    def test_samples_vary(self):
+        self.announce(10)
+        for _ in range(5):
+            self.run_check("--sample-above", "2", "--sample-size", "1")
+        self.assertGreater(self.probed(), 1)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,39 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class SamplingTest(CheckTestCase):
+    def sample(self, ratio):
+        return self.run_check(
+            "--probe-interval",
+            "0.1",
+            "--probe-window",
+            "0.35",
+            "--min-success-ratio",
+            ratio,
+        )
+
+    def test_flapping_instance_below_ratio_is_critical(self):
+        self.fake.announce("a", statuses=[200, 500] * 5)
+        code, lines = self.sample("0.9")
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "samples succeeded")
+        self.assertGreater(self.fake.probes()["a"], 1)
+
+    def test_flapping_instance_within_ratio_is_ok(self):
+        self.fake.announce("a", statuses=[200, 500] * 5)
+        code, lines = self.sample("0.5")
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "samples succeeded")
+
+    def test_all_failing_with_zero_ratio(self):
+        # No sample passed, so the failure is what's reported.
+        self.fake.announce("a", statuses=[500])
+        code, lines = self.sample("0")
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "0 of ")
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,59 @@
+import json
+import os
+import time
+import unittest
+
+from helpers import CheckTestCase
+
+
+class SaveResponsesTest(CheckTestCase):
+    def setUp(self):
+        super(SaveResponsesTest, self).setUp()
+        self.fake.announce("a", bodies=(b'{"status": "UP"}',))
+        self.fake.announce("b", statuses=(503,), bodies=(b"draining",))
+        self.dir = self.path("responses")
+        os.mkdir(self.dir)
+        self.port = self.fake.url.rsplit(":", 1)[1].strip("/")
+
+    def read(self, name):
+        with open(os.path.join(self.dir, name), "rb") as f:
+            return f.read()
+
+    def test_files(self):
+        started = time.time()
+        code, lines = self.run_check("--save-responses", self.dir)
+        self.assertEqual(code, 2)
+        a = "health_127.0.0.1_%s_a_health" % self.port
+        b = "health_127.0.0.1_%s_b_health" % self.port
+        self.assertEqual(
+            sorted(os.listdir(self.dir)),
+            [a + ".body", a + ".json", b + ".body", b + ".json"],
+        )
+        self.assertEqual(self.read(a + ".body"), b'{"status": "UP"}')
+        self.assertEqual(self.read(b + ".body"), b"draining")
+        meta = json.loads(self.read(b + ".json").decode("utf-8"))
+        self.assertEqual(meta["uri"], self.fake.uri("b") + "health")
+        self.assertEqual(meta["endpoint"], "health")
+        self.assertEqual(meta["status"], 503)
+        self.assertEqual(meta["code"], 2)
+        self.assertTrue(started <= meta["timestamp"] <= time.time())
+        self.assertEqual(json.loads(self.read(a + ".json").decode("utf-8"))["code"], 0)
+
+    def test_overwrites_previous_run(self):
+        self.run_check("--save-responses", self.dir)
+        self.fake.instances["b"].bodies = [b"back"]
+        self.fake.instances["b"].statuses = [200]
+        code, lines = self.run_check("--save-responses", self.dir)
+        self.assertEqual(code, 0)
+        self.assertEqual(len(os.listdir(self.dir)), 4)
+        b = "health_127.0.0.1_%s_b_health" % self.port
+        self.assertEqual(self.read(b + ".body"), b"back")
+
+    def test_unwritable_directory_warns(self):
+        code, lines = self.run_check("--save-responses", self.path("missing"))
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "failed to save 2 responses")
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,47 @@
+import unittest
+
+from helpers import CheckTestCase
+
+SERVICES = """# Owned by the storefront team
+svc
+other   # added for the migration
+
+  # retired: legacy
+third
+"""
+
+
+class ServiceFileTest(CheckTestCase):
+    def setUp(self):
+        super(ServiceFileTest, self).setUp()
+        with open(self.path("services"), "w") as f:
+            f.write(SERVICES)
+        self.fake.announce("a")
+        self.fake.announce("b", serviceType="other")
+        self.fake.announce("c", serviceType="legacy")
+
+    def base_args(self):
+        return ["-d", self.fake.url, "--service-file", self.path("services")]
+
+    def test_services_from_file(self):
+        self.assertEqual(self.main().services, ["svc", "other", "third"])
+        code, lines = self.run_check("-w", "1", "-c", "1")
+        # third has no announcements.
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "svc announcements ok: 1")
+        self.assertLine(lines, "other announcements ok: 1")
+        self.assertLine(lines, "third announcements critical: 0")
+        self.assertEqual(self.fake.probes(), {"a": 1, "b": 1, "c": 0})
+
+    def test_combined_with_service_flags(self):
+        main = self.main("-s", "legacy", "-s", "other")
+        self.assertEqual(main.services, ["legacy", "other", "svc", "third"])
+
+    def test_missing_file(self):
+        self.assertParserError(
+            ["--service-file", self.path("missing")], "cannot read service file"
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,49 @@
+import unittest
+
+from helpers import CheckTestCase
+
+NAMES = ["i%02d" % i for i in range(20)]
+
+
+class ShardTest(CheckTestCase):
+    def setUp(self):
+        super(ShardTest, self).setUp()
+        for name in NAMES:
+            self.fake.announce(name)
+
+    def probed(self):
+        # Instances probed since the last call.
+        probed = set()
+        for name, instance in self.fake.instances.items():
+            if instance.requests:
+                probed.add(name)
+                del instance.requests[:]
+        return probed
+
+    def test_shards_disjoint_and_complete(self):
+        shards = []
+        for index in range(3):
+            args = ["--shard-count", "3", "--shard-index", str(index)]
+            code, lines = self.run_check(*args)
+            self.assertEqual(code, 0)
+            # Quota still counts every instance.
+            self.assertLine(lines, "announcements ok: 20")
+            shards.append(self.probed())
+        self.assertEqual(sorted(sum(map(list, shards), [])), NAMES)
+        self.assertTrue(all(shards), shards)
+
+    def test_stable(self):
+        self.run_check("--shard-count", "3", "--shard-index", "1")
+        first = self.probed()
+        self.run_check("--shard-count", "3", "--shard-index", "1")
+        self.assertEqual(self.probed(), first)
+
+    def test_index_out_of_range(self):
+        self.assertParserError(
+            ["--shard-count", "3", "--shard-index", "3"],
+            "shard-index must be in [0, shard-count)",
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
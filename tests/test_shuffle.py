@@ -0,0 +1,39 @@
+import re
+import unittest
+
+from helpers import CheckTestCase
+
+NAMES = "abcdefgh"
+
+
+class ShuffleTest(CheckTestCase):
+    def setUp(self):
+        super(ShuffleTest, self).setUp()
+        for name in NAMES:
+            self.fake.announce(name)
+
+    def probe_order(self, *args):
+        # One at a time, so instance perf data is in probe order.
+        code, lines = self.run_check("--concurrency", "1", *args)
+        self.assertEqual(code, 0)
+        return "".join(re.findall(r"_([a-h])=\d+ms", lines[0]))
+
+    def test_unshuffled_order(self):
+        self.assertEqual(self.probe_order(), NAMES)
+
+    def test_seeds(self):
+        first = self.probe_order("--shuffle", "1")
+        second = self.probe_order("--shuffle", "2")
+        self.assertNotEqual(first, second)
+        for order in (first, second):
+            self.assertEqual(sorted(order), list(NAMES))
+        self.assertEqual(self.probe_order("--shuffle", "1"), first)
+        self.assertEqual(self.fake.probes(), dict.fromkeys(NAMES, 3))
+
+    def test_unseeded(self):
+        order = self.probe_order("--shuffle")
+        self.assertEqual(sorted(order), list(NAMES))
+
+
+if __name__ == "__main__":
+    unittest.main()
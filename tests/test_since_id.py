@@ -0,0 +1,44 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class SinceIdTest(CheckTestCase):
+    def setUp(self):
+        super(SinceIdTest, self).setUp()
+        self.old = [
+            self.fake.announce(name, statuses=(500,), announcementId=id)
+            for name, id in (("a", "2024-01-01-001"), ("b", "2024-01-01-002"))
+        ]
+        self.new = [
+            self.fake.announce(name, announcementId=id)
+            for name, id in (("c", "2024-01-02-001"), ("d", "2024-01-02-002"))
+        ]
+
+    def test_probes_only_after_cutoff(self):
+        code, lines = self.run_check("--since-id", "2024-01-01-002")
+        self.assertEqual(code, 0)
+        self.assertEqual([i.probes for i in self.old], [0, 0])
+        self.assertEqual([i.probes for i in self.new], [1, 1])
+
+    def test_cutoff_is_exclusive(self):
+        code, lines = self.run_check("--since-id", "2024-01-01-001")
+        self.assertEqual(code, 2)
+        self.assertEqual([i.probes for i in self.old], [0, 1])
+
+    def test_quota_counts_all(self):
+        # Four announced, only two probed, but a quota of 4 is met.
+        since = ["--since-id", "2024-01-01-002"]
+        code, lines = self.run_check(*since + ["-c", "4", "-w", "4"])
+        self.assertEqual(code, 0)
+        code, lines = self.run_check(*since + ["-c", "5", "-w", "5"])
+        self.assertEqual(code, 2)
+
+    def test_without_flag_probes_all(self):
+        code, lines = self.run_check()
+        self.assertEqual(code, 2)
+        self.assertEqual([i.probes for i in self.old + self.new], [1, 1, 1, 1])
+
+
+if __name__ == "__main__":
+    unittest.main()
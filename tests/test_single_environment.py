@@ -0,0 +1,42 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class SingleEnvironmentTest(CheckTestCase):
+    def test_two_environments_warn(self):
+        for name in "abc":
+            self.fake.announce(name)
+        self.fake.announce("d", environment="staging")
+        code, lines = self.run_check("--require-single-environment")
+        self.assertEqual(code, 1)
+        msg = "environments warning: svc announcements in 2 environments, most in prod"
+        self.assertLine(lines, msg)
+        self.assertIn("staging %s" % self.fake.uri("d"), lines)
+        # Only the outliers are listed.
+        self.assertFalse(any(line.startswith("prod ") for line in lines))
+        # Still health checked, though.
+        self.assertEqual(self.fake.probes(), dict.fromkeys("abcd", 1))
+
+    def test_one_environment_ok(self):
+        for name in "ab":
+            self.fake.announce(name)
+        code, lines = self.run_check("--require-single-environment")
+        self.assertEqual(code, 0)
+        self.assertFalse(any(line.startswith("environments") for line in lines))
+
+    def test_per_service(self):
+        self.fake.announce("a")
+        self.fake.announce("b", serviceType="other", environment="staging")
+        code, lines = self.run_check("-s", "other", "--require-single-environment")
+        self.assertEqual(code, 0)
+
+    def test_off_by_default(self):
+        self.fake.announce("a")
+        self.fake.announce("b", environment="staging")
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+
+
+if __name__ == "__main__":
+    unittest.main()
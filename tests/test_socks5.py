@@ -0,0 +1,36 @@
+import unittest
+
+from helpers import CheckTestCase, FakeSocks5
+
+try:
+    import socks  # what requests needs for SOCKS proxies
+except ImportError:
+    socks = None
+
+
+@unittest.skipIf(socks is None, "PySocks isn't installed")
+class Socks5Test(CheckTestCase):
+    def setUp(self):
+        super(Socks5Test, self).setUp()
+        self.proxy = FakeSocks5()
+        self.addCleanup(self.proxy.close)
+        port = self.fake.url.rsplit(":", 1)[1].strip("/")
+        self.port = int(port)
+        self.fake.announce("a", serviceUri="http://localhost:%s/a/" % port)
+
+    def test_probes_go_through_proxy(self):
+        code, lines = self.run_check("--socks5", self.proxy.address)
+        self.assertEqual(code, 0)
+        # The proxy, not us, resolves the name.
+        self.assertEqual(self.proxy.connects, [("localhost", self.port)])
+        self.assertEqual(self.fake.probes(), {"a": 1})
+
+    def test_unreachable_proxy(self):
+        self.proxy.close()
+        code, lines = self.run_check("--socks5", self.proxy.address)
+        self.assertEqual(code, 2)
+        self.assertEqual(self.fake.probes(), {"a": 0})
+
+
+if __name__ == "__main__":
+    unittest.main()
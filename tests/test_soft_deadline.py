@@ -0,0 +1,26 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class SoftDeadlineTest(CheckTestCase):
+    def test_probes_after_deadline_are_skipped(self):
+        for name in "abcdef":
+            self.fake.announce(name, delay=0.5)
+        code, lines = self.run_check("--soft-deadline", "0.3", "--concurrency", "2")
+        self.assertEqual(code, 3)
+        self.assertLine(lines, "health ok")
+        self.assertLine(lines, "skipped, soft deadline 0.300s passed")
+        # Only the first batch reached the instances.
+        self.assertEqual(sum(self.fake.probes().values()), 2)
+
+    def test_everything_checked_before_deadline(self):
+        for name in "abc":
+            self.fake.announce(name)
+        code, lines = self.run_check("--soft-deadline", "5")
+        self.assertEqual(code, 0)
+        self.assertNotIn("skipped", "\n".join(lines))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,39 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class StateEnvelopeTest(CheckTestCase):
+    def setUp(self):
+        super(StateEnvelopeTest, self).setUp()
+        self.fake.announce("a")
+        self.fake.announce("b")
+
+    def test_enveloped_state(self):
+        self.fake.state_wrap = lambda anns: {"announcements": anns, "generation": 123}
+        code, lines = self.run_check("--state-envelope-field", "announcements")
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "announcements ok: 2")
+        self.assertLine(lines, "disco generation: 123")
+        self.assertIn(" generation=123 ", lines[0])
+
+    def test_enveloped_state_without_generation(self):
+        self.fake.state_wrap = lambda anns: {"announcements": anns}
+        code, lines = self.run_check("--state-envelope-field", "announcements")
+        self.assertEqual(code, 0)
+        self.assertNotIn("disco generation", "\n".join(lines))
+
+    def test_missing_field(self):
+        self.fake.state_wrap = lambda anns: {"items": anns}
+        code, lines = self.run_check("--state-envelope-field", "announcements")
+        self.assertEqual(code, 3)
+        self.assertLine(lines, "discovery state has no 'announcements' field")
+
+    def test_bare_array_needs_no_flag(self):
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "announcements ok: 2")
+
+
+if __name__ == "__main__":
+    unittest.main()
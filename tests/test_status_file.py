@@ -0,0 +1,48 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class StatusFileTest(CheckTestCase):
+    def status(self):
+        with open(self.path("status")) as f:
+            return f.read()
+
+    def run_status(self, *args):
+        return self.run_check("--status-file", self.path("status"), *args)
+
+    def test_ok(self):
+        self.fake.announce("a")
+        self.assertEqual(self.run_status()[0], 0)
+        self.assertEqual(self.status(), "OK health ok: 200 from endpoint\n")
+
+    def test_critical(self):
+        self.fake.announce("a", statuses=[500])
+        self.assertEqual(self.run_status()[0], 2)
+        self.assertTrue(self.status().startswith("CRITICAL "), self.status())
+
+    def test_bad_arguments(self):
+        self.assertParserError(
+            ["--status-file", self.path("status"), "--removal-grace", "5"],
+            "removal-grace requires removal-state",
+        )
+        self.assertEqual(
+            self.status(), "UNKNOWN error: removal-grace requires removal-state\n"
+        )
+
+    def test_discovery_failure(self):
+        self.fake.close()
+        self.assertEqual(self.run_status()[0], 3)
+        self.assertEqual(self.status(), "UNKNOWN failed to get announcements\n")
+
+    def test_total_timeout(self):
+        self.fake.announce("a", delay=3)
+        self.assertEqual(self.run_status("--total-timeout", "0.5")[0], 3)
+        self.assertEqual(
+            self.status(),
+            "UNKNOWN results unknown: partial results due to total timeout 0.500s\n",
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,36 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class StatusFloorTest(CheckTestCase):
+    def test_all_ok_reports_floor(self):
+        for name in "ab":
+            self.fake.announce(name)
+        code, lines = self.run_check("--status-floor", "warning")
+        self.assertEqual(code, 1)
+        first = lines[0].split(" | ")[0]
+        self.assertEqual(first, "status floor warning: worst result was ok")
+        # The instances themselves are still ok.
+        self.assertIn(" ok=2 warn=0 ", lines[0])
+
+    def test_unknown_floor(self):
+        self.fake.announce("a")
+        code, lines = self.run_check("--status-floor", "unknown")
+        self.assertEqual(code, 3)
+
+    def test_worse_results_win(self):
+        self.fake.announce("a", statuses=(500,))
+        code, lines = self.run_check("--status-floor", "warning")
+        self.assertEqual(code, 2)
+        self.assertFalse(any(line.startswith("status floor") for line in lines))
+
+    def test_at_floor(self):
+        self.fake.announce("a", statuses=(404,))
+        code, lines = self.run_check("--status-floor", "warning")
+        self.assertEqual(code, 1)
+        self.assertFalse(any(line.startswith("status floor") for line in lines))
+
+
+if __name__ == "__main__":
+    unittest.main()
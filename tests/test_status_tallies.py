@@ -0,0 +1,40 @@
+import re
+import unittest
+
+from helpers import CheckTestCase
+
+
+class StatusTalliesTest(CheckTestCase):
+    def tallies(self, lines):
+        perf = dict(re.findall(r"(\w+)=(\d+)", lines[0].split(" | ", 1)[1]))
+        return [int(perf[name]) for name in ("ok", "warn", "crit", "unknown")]
+
+    def test_known_mix(self):
+        for name in "ab":
+            self.fake.announce(name)
+        self.fake.announce("c", statuses=(404,))
+        for name in "de":
+            self.fake.announce(name, statuses=(500,))
+        self.fake.announce("f", statuses=(401,))
+        # Too few instances, too: the quota result isn't counted.
+        code, lines = self.run_check(
+            "--auth-failure-status", "unknown", "-c", "10", "-w", "10"
+        )
+        self.assertEqual(code, 2)
+        self.assertLine(lines, "announcements critical: 6")
+        self.assertEqual(self.tallies(lines), [2, 1, 2, 1])
+
+    def test_all_ok(self):
+        for name in "abc":
+            self.fake.announce(name)
+        code, lines = self.run_check()
+        self.assertEqual(self.tallies(lines), [3, 0, 0, 0])
+
+    def test_no_health_checks(self):
+        self.fake.announce("a")
+        code, lines = self.run_check("--no-healthcheck")
+        self.assertNotIn(" ok=", lines[0])
+
+
+if __name__ == "__main__":
+    unittest.main()
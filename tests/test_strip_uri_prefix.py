@@ -0,0 +1,35 @@
+import unittest
+
+from helpers import CheckTestCase, check
+
+
+class StripUriPrefixTest(CheckTestCase):
+    def test_prefixed_and_unprefixed(self):
+        prefixed = self.fake.announce("a", serviceUri=self.fake.url + "gw/a/")
+        plain = self.fake.announce("b")
+        code, lines = self.run_check("--strip-uri-prefix", "/gw")
+        self.assertEqual(code, 0)
+        self.assertEqual(prefixed.requests[0].path, "/a/health")
+        self.assertEqual(plain.requests[0].path, "/b/health")
+
+    def test_without_flag(self):
+        self.fake.announce("a", serviceUri=self.fake.url + "gw/a/")
+        code, lines = self.run_check()
+        self.assertNotEqual(code, 0)
+        self.assertLine(lines, "404")
+
+    def test_rewrites(self):
+        cases = [
+            ("http://h/gw/a/", "/gw", "http://h/a/"),
+            ("http://h/gw/a/", "gw/", "http://h/a/"),
+            ("http://h/gw", "/gw", "http://h/"),
+            ("http://h/gwx/a/", "/gw", "http://h/gwx/a/"),
+            ("http://h/a/gw/", "/gw", "http://h/a/gw/"),
+            ("http://h:8080/gw/a/?q=1", "/gw", "http://h:8080/a/?q=1"),
+        ]
+        for uri, prefix, expected in cases:
+            self.assertEqual(check.strip_uri_prefix(uri, prefix), expected, uri)
+
+
+if __name__ == "__main__":
+    unittest.main()
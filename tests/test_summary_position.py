@@ -0,0 +1,36 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class SummaryPositionTest(CheckTestCase):
+    def setUp(self):
+        super(SummaryPositionTest, self).setUp()
+        self.fake.announce("a", statuses=(500,))
+        self.fake.announce("b")
+
+    def test_top(self):
+        for args in ((), ("--summary-position", "top")):
+            code, lines = self.run_check(*args)
+            self.assertEqual(code, 2)
+            first, perf = lines[0].split(" | ")
+            self.assertEqual(first, "health critical: 500 from endpoint")
+            self.assertIn("instances=2", perf)
+            self.assertGreater(lines.index("health ok: 200 from endpoint"), 0)
+
+    def test_bottom(self):
+        code, lines = self.run_check("--summary-position", "bottom")
+        self.assertEqual(code, 2)
+        summary = lines.index("health critical: 500 from endpoint")
+        # The worst result comes last, after the rest of the detail.
+        self.assertEqual(lines[summary - 1], "---")
+        self.assertEqual(lines[-2], "check URI %shealth" % self.fake.uri("a"))
+        # Perf data stays on the first line, which Nagios reads it from.
+        first, perf = lines[0].split(" | ")
+        self.assertEqual(first, "health ok: 200 from endpoint")
+        self.assertIn("instances=2", perf)
+        self.assertEqual(sum(" | " in line for line in lines), 1)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,87 @@
+import io
+import os
+import re
+import socket
+import unittest
+from contextlib import redirect_stderr
+from unittest import mock
+
+from helpers import CheckTestCase, check
+
+DEVLOG = "/dev/log"
+# "<PRI>Mmm dd hh:mm:ss TAG: MESSAGE", as the C library sends it
+MESSAGE = re.compile(r"^<(\d+)>\w{3} [ \d]\d \d\d:\d\d:\d\d ([^:]+): (.*)$")
+
+
+def can_listen():
+    # Only where nothing else is listening, and we may take its place.
+    if check.syslog is None or not hasattr(socket, "AF_UNIX"):
+        return False
+    return not os.path.exists(DEVLOG) and os.access(os.path.dirname(DEVLOG), os.W_OK)
+
+
+class SyslogTest(CheckTestCase):
+    def setUp(self):
+        super(SyslogTest, self).setUp()
+        self.fake.announce("a")
+        self.fake.announce("b", statuses=(500,))
+
+    def listen(self):
+        sock = socket.socket(socket.AF_UNIX, socket.SOCK_DGRAM)
+        sock.bind(DEVLOG)
+        self.addCleanup(os.unlink, DEVLOG)
+        self.addCleanup(sock.close)
+        sock.settimeout(5)
+        return sock
+
+    def receive(self, sock, count):
+        # (priority, tag, message) of each syslog datagram
+        messages = []
+        for _ in range(count):
+            data = sock.recv(65536).decode("utf-8")
+            match = MESSAGE.match(data)
+            self.assertIsNotNone(match, data)
+            pri, tag, message = match.groups()
+            messages.append((int(pri), tag, message.rstrip("\0")))
+        return messages
+
+    @unittest.skipUnless(can_listen(), "cannot listen on %s" % DEVLOG)
+    def test_listener_receives_results(self):
+        sock = self.listen()
+        code, lines = self.run_check("--syslog")
+        self.assertEqual(code, 2)
+        messages = self.receive(sock, 4)
+        # user facility: 8 + err 3, info 6
+        self.assertEqual(
+            [pri for pri, _, _ in messages], [8 + 3, 8 + 6, 8 + 6, 8 + 3]
+        )
+        self.assertEqual(set(tag for _, tag, _ in messages), {"otpl-service-check"})
+        self.assertIn("uri=%shealth" % self.fake.uri("b"), messages[0][2])
+        self.assertIn("topic=summary status=critical code=2", messages[-1][2])
+
+    @unittest.skipUnless(can_listen(), "cannot listen on %s" % DEVLOG)
+    def test_listener_facility_and_tag(self):
+        sock = self.listen()
+        self.run_check("--syslog", "--syslog-facility", "local3", "--syslog-tag", "hc")
+        pri, tag, _ = self.receive(sock, 1)[0]
+        self.assertEqual((pri, tag), (19 * 8 + 3, "hc"))
+
+    @unittest.skipIf(check.syslog is None, "no syslog module")
+    def test_failure_keeps_exit_code(self):
+        err = io.StringIO()
+        failing = mock.Mock(side_effect=OSError("no syslog"))
+        with mock.patch.object(check.syslog, "syslog", failing), redirect_stderr(err):
+            code, lines = self.run_check("--syslog")
+        self.assertEqual(code, 2)
+        self.assertIn("failed to write syslog: no syslog", err.getvalue())
+
+    def test_unavailable_platform(self):
+        err = io.StringIO()
+        with mock.patch.object(check, "syslog", None), redirect_stderr(err):
+            code, lines = self.run_check("--syslog")
+        self.assertEqual(code, 2)
+        self.assertIn("syslog is not available", err.getvalue())
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,73 @@
+import os
+import shutil
+import unittest
+
+from helpers import TLS, CheckTestCase, check
+
+
+class TlsDirTest(CheckTestCase):
+    def setUp(self):
+        super(TlsDirTest, self).setUp()
+        self.fake.serve_tls()
+        self.instance = self.fake.announce("a", serviceUri=self.fake.tls_uri("a"))
+
+    def tls_dir(self, *names):
+        path = self.path("tls")
+        os.mkdir(path)
+        for name in names:
+            shutil.copy(os.path.join(TLS, name), path)
+        return path
+
+    def test_ca_and_client_cert(self):
+        code, lines = self.run_check("--tls-dir", TLS)
+        self.assertEqual(code, 0)
+        self.assertEqual(self.instance.requests[0].peer, {"commonName": "test client"})
+
+    def test_config_from_temp_dir(self):
+        path = self.tls_dir("ca.pem", "cert.pem", "key.pem")
+        main = self.main("--tls-dir", path)
+        self.assertEqual(main.verify, os.path.join(path, "ca.pem"))
+        self.assertEqual(
+            main.cert, (os.path.join(path, "cert.pem"), os.path.join(path, "key.pem"))
+        )
+        code, lines = self.run_check("--tls-dir", path)
+        self.assertEqual(code, 0)
+        self.assertEqual(self.instance.requests[0].peer, {"commonName": "test client"})
+
+    def test_ca_alone(self):
+        path = self.tls_dir("ca.pem")
+        self.assertEqual(check.read_tls_dir(path), (os.path.join(path, "ca.pem"), None))
+        code, lines = self.run_check("--tls-dir", path)
+        self.assertEqual(code, 0)
+        self.assertIsNone(self.instance.requests[0].peer)
+
+    def test_client_cert_keeps_ca_file(self):
+        path = self.tls_dir("cert.pem", "key.pem")
+        ca = os.path.join(TLS, "ca.pem")
+        code, lines = self.run_check("--tls-dir", path, "--ca-file", ca)
+        self.assertEqual(code, 0)
+        self.assertEqual(self.instance.requests[0].peer, {"commonName": "test client"})
+
+    def test_client_cert_alone_uses_system_cas(self):
+        path = self.tls_dir("cert.pem", "key.pem")
+        code, lines = self.run_check("--tls-dir", path)
+        self.assertEqual(code, 2)
+        self.assertEqual(self.instance.probes, 0)
+
+    def test_two_cas_are_ambiguous(self):
+        ca = os.path.join(TLS, "ca.pem")
+        self.assertParserError(["--tls-dir", TLS, "--ca-file", ca], "ambiguous")
+
+    def test_empty_dir_rejected(self):
+        path = self.tls_dir()
+        self.assertParserError(["--tls-dir", path], "no ca.pem or cert.pem and key.pem")
+
+    def test_missing_pair_rejected(self):
+        path = self.tls_dir("cert.pem")
+        self.assertParserError(
+            ["--tls-dir", path], "cert.pem and key.pem must both be present"
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,62 @@
+import time
+import unittest
+
+from helpers import CheckTestCase
+
+
+class TotalTimeoutTest(CheckTestCase):
+    def setUp(self):
+        super(TotalTimeoutTest, self).setUp()
+        # Discovery uses up some of the budget before the slow instance
+        # uses up the rest.
+        self.fake.state_delay = 0.3
+        self.fake.announce("fast")
+        self.fake.announce("slow", delay=3)
+
+    def run_timeout(self, *args):
+        started = time.time()
+        code, lines = self.run_check("--total-timeout", "0.8", *args)
+        self.assertLess(time.time() - started, 2)
+        self.assertEqual(code, 3)
+        return lines
+
+    def test_slow_instance_gives_partial_results(self):
+        lines = self.run_timeout()
+        # The banner leads, and so carries the perf data.
+        first, _, perfdata = lines[0].partition(" | ")
+        self.assertEqual(
+            first, "results unknown: partial results due to total timeout 0.800s"
+        )
+        self.assertIn("fast=", perfdata)
+        self.assertLine(lines, "check URI %sfast/health" % self.fake.url)
+
+    def test_slow_discovery_alone_times_out(self):
+        self.fake.state_delay = 2
+        code, lines = self.run_check("--total-timeout", "0.5")
+        self.assertEqual(code, 3)
+        self.assertEqual(lines[0], "failed to get announcements")
+
+    def test_logfmt_marks_summary_partial(self):
+        lines = self.run_timeout("--output", "logfmt")
+        self.assertTrue(lines[-1].startswith("topic=summary "), lines[-1])
+        self.assertIn(" status=unknown code=3 ", lines[-1])
+        self.assertTrue(lines[-1].endswith(" partial=true"), lines[-1])
+        # Every line is a record; there's no banner.
+        self.assertTrue(all(line.startswith("topic=") for line in lines), lines)
+
+    def test_openmetrics_marks_document_partial(self):
+        lines = self.run_timeout("--output", "openmetrics")
+        self.assertTrue(lines[0].startswith("# TYPE "), lines[0])
+        self.assertIn("otpl_partial_results 1", lines)
+        self.assertEqual(lines[-1], "# EOF")
+
+    def test_no_banner_within_timeout(self):
+        self.fake.state_delay = 0
+        self.fake.withdraw("slow")
+        code, lines = self.run_check("--total-timeout", "5", "--output", "logfmt")
+        self.assertEqual(code, 0)
+        self.assertNotIn("partial", "\n".join(lines))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,46 @@
+import os
+import re
+import unittest
+
+from helpers import TLS, CheckTestCase, check
+
+
+class TraceTest(CheckTestCase):
+    def phases(self, lines):
+        traces = [line for line in lines if line.startswith("trace ")]
+        self.assertEqual(len(traces), 1, lines)
+        pairs = re.findall(r"(\w+) (-?[\d.]+)s", traces[0])
+        return [(name, float(secs)) for name, secs in pairs]
+
+    def test_http_phases(self):
+        self.fake.announce("a")
+        code, lines = self.run_check("--trace")
+        self.assertEqual(code, 0)
+        phases = self.phases(lines)
+        self.assertEqual([name for name, _ in phases], ["dns", "connect", "ttfb"])
+        self.assertTrue(all(secs >= 0 for _, secs in phases), phases)
+
+    def test_https_phases(self):
+        self.fake.serve_tls()
+        self.fake.announce("a", serviceUri=self.fake.tls_uri("a"))
+        code, lines = self.run_check(
+            "--trace", "--ca-file", os.path.join(TLS, "ca.pem")
+        )
+        self.assertEqual(code, 0)
+        phases = self.phases(lines)
+        names = [name for name, _ in phases]
+        self.assertEqual(names, ["dns", "connect", "tls", "ttfb"])
+        self.assertTrue(all(secs >= 0 for _, secs in phases), phases)
+
+    def test_no_trace_without_flag(self):
+        self.fake.announce("a")
+        code, lines = self.run_check()
+        self.assertFalse(any(line.startswith("trace ") for line in lines))
+
+    def test_failed_connection_has_no_phases(self):
+        # The real request reports the failure instead.
+        self.assertIsNone(check.trace_connection("http://127.0.0.1:1/", 1))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,56 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class TransportErrorRateTest(CheckTestCase):
+    def rate_args(self, warn, crit):
+        return [
+            "-t",
+            "1",
+            "--transport-error-rate-warn",
+            warn,
+            "--transport-error-rate-crit",
+            crit,
+        ]
+
+    def test_half_time_out(self):
+        for name in "ab":
+            self.fake.announce(name, delay=2)
+        for name in "cd":
+            self.fake.announce(name)
+        code, lines = self.run_check(*self.rate_args("0.25", "0.75"))
+        self.assertEqual(code, 2)
+        msg = "transport errors warning: 2 of 4 health checks got no HTTP response"
+        self.assertLine(lines, msg)
+        self.assertLine(lines, "crit./warn thresh.: 75%/25%")
+
+        code, lines = self.run_check(*self.rate_args("0.1", "0.4"))
+        msg = "transport errors critical: 2 of 4 health checks got no HTTP response"
+        self.assertLine(lines, msg)
+
+    def test_http_failures_not_counted(self):
+        for name in "ab":
+            self.fake.announce(name, statuses=(500,))
+        for name in "cd":
+            self.fake.announce(name)
+        code, lines = self.run_check(*self.rate_args("0", "0"))
+        self.assertEqual(code, 2)
+        self.assertFalse(any("transport errors" in line for line in lines))
+
+    def test_below_threshold(self):
+        self.fake.announce("a", delay=2)
+        for name in "bcd":
+            self.fake.announce(name)
+        code, lines = self.run_check(*self.rate_args("0.25", "0.75"))
+        self.assertFalse(any("transport errors" in line for line in lines))
+
+    def test_crit_requires_warn(self):
+        self.assertParserError(
+            ["--transport-error-rate-crit", "0.5"],
+            "transport-error-rate-crit requires transport-error-rate-warn",
+        )
+
+
+if __name__ == "__main__":
+    unittest.main()
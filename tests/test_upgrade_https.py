@@ -0,0 +1,41 @@
+import os
+import unittest
+
+from helpers import TLS, CheckTestCase, check
+
+
+class UpgradeHttpsTest(CheckTestCase):
+    def test_probe_uses_https_on_next_port(self):
+        self.fake.serve_tls()
+        port = int(self.fake.tls_url.rsplit(":", 1)[1].strip("/"))
+        uri = "http://localhost:%s/a/" % (port - 1)
+        a = self.fake.announce("a", serviceUri=uri)
+        code, lines = self.run_check(
+            "--upgrade-https", "1", "--ca-file", os.path.join(TLS, "ca.pem")
+        )
+        self.assertEqual(code, 0)
+        self.assertEqual(a.probes, 1)
+        self.assertLine(lines, "check URI %sa/health" % self.fake.tls_url)
+
+    def test_without_flag(self):
+        self.fake.announce("a")
+        code, lines = self.run_check()
+        self.assertEqual(code, 0)
+        self.assertLine(lines, "check URI %sa/health" % self.fake.url)
+
+    def test_rewrites(self):
+        cases = [
+            ("http://h:8080/p", 0, "https://h:8080/p"),
+            ("http://h:8080/p", 1, "https://h:8081/p"),
+            ("http://h/p", 0, "https://h/p"),
+            ("http://h/p", 363, "https://h:443/p"),
+            ("http://[::1]:80/", 1, "https://[::1]:81/"),
+            ("http://u:pw@h:80/", 1, "https://u:pw@h:81/"),
+            ("https://h:8443/p", 1, "https://h:8443/p"),
+        ]
+        for uri, offset, expected in cases:
+            self.assertEqual(check.upgrade_https(uri, offset), expected, uri)
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,42 @@
+import unittest
+
+from helpers import CheckTestCase
+
+INVALID = {
+    "b": "not a uri",
+    "c": "ftp://127.0.0.1/c/",
+    "d": "http://127.0.0.1:99999/d/",
+    "e": "http://[::1/e/",
+    "f": None,
+}
+
+
+class ValidateUrisTest(CheckTestCase):
+    def setUp(self):
+        super(ValidateUrisTest, self).setUp()
+        self.valid = self.fake.announce("a")
+        for name, uri in sorted(INVALID.items()):
+            self.fake.announce(name, serviceUri=uri)
+
+    def test_invalid_uris_in_one_warning(self):
+        code, lines = self.run_check("--validate-uris")
+        self.assertEqual(code, 1)
+        msg = "service URIs warning: 5 announcements with invalid URIs"
+        self.assertLine(lines, msg)
+        for name, uri in sorted(INVALID.items()):
+            self.assertIn("%s svc %r" % (name, uri), lines)
+        self.assertEqual(self.valid.probes, 1)
+        # Health results only for the valid one; all are still counted.
+        self.assertEqual(sum(line.startswith("health ") for line in lines), 1)
+        self.assertIn(" instances=6 ", lines[0])
+
+    def test_all_valid(self):
+        for name in INVALID:
+            self.fake.withdraw(name)
+        code, lines = self.run_check("--validate-uris")
+        self.assertEqual(code, 0)
+        self.assertFalse(any("service URIs" in line for line in lines))
+
+
+if __name__ == "__main__":
+    unittest.main()
@@ -0,0 +1,33 @@
+import unittest
+
+from helpers import CheckTestCase
+
+
+class WarnOnlyTest(CheckTestCase):
+    def test_critical_capped(self):
+        self.fake.announce("a", statuses=[500])
+        code, lines = self.run_check("--warn-only")
+        self.assertEqual(code, 1)
+        # The wording stays; the cap is noted.
+        self.assertLine(lines, "health critical: 500")
+        self.assertLine(lines, "(capped at warning)")
+
+    def test_quota_critical_capped(self):
+        code, lines = self.run_check("--warn-only")
+        self.assertEqual(code, 1)
+        self.assertLine(lines, "announcements critical: 0")
+
+    def test_without_flag(self):
+        self.fake.announce("a", statuses=[500])
+        code, lines = self.run_check()
+        self.assertEqual(code, 2)
+        self.assertNotIn("capped", "\n".join(lines))
+
+    def test_ok_untouched(self):
+        self.fake.announce("a")
+        code, lines = self.run_check("--warn-only")
+        self.assertEqual(code, 0)
+
+
+if __name__ == "__main__":
+    unittest.main()
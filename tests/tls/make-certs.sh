@@ -0,0 +1,22 @@
+#!/bin/sh
+# Regenerates the test CA, the localhost server certificate, and a client
+# certificate.  They're only for the tests, so they're long-lived.
+set -e
+cd "$(dirname "$0")"
+days=36500
+
+openssl req -x509 -newkey rsa:2048 -nodes -days $days -subj "/CN=test ca" \
+    -keyout ca.key -out ca.pem
+
+openssl req -newkey rsa:2048 -nodes -subj "/CN=localhost" \
+    -keyout server.key -out server.csr
+printf "subjectAltName=DNS:localhost,IP:127.0.0.1,IP:::1\n" > server.ext
+openssl x509 -req -in server.csr -CA ca.pem -CAkey ca.key -CAcreateserial \
+    -days $days -extfile server.ext -out server.pem
+
+openssl req -newkey rsa:2048 -nodes -subj "/CN=test client" \
+    -keyout key.pem -out client.csr
+openssl x509 -req -in client.csr -CA ca.pem -CAkey ca.key -CAcreateserial \
+    -days $days -out cert.pem
+
+rm -f ca.key ca.srl server.csr server.ext client.csr